@@ -1,31 +1,30 @@
 package algnum
 
 import (
+	"context"
 	"errors"
-	"gonum.org/v1/gonum/mat"
 	"math"
-	"sync"
 )
 
 func forwElim(a *Matrix, f []float64) (*Matrix, []float64, error) {
-	resMat := &Matrix{data: copy2dSlice(a.data), rows: a.rows, cols: a.cols}
+	resMat := a.Clone()
 	resF := make([]float64, len(f))
 	copy(resF, f)
 
-	for i := range resMat.data {
-		leadEl := resMat.data[i][i]
+	for i := 0; i < resMat.rows; i++ {
+		leadEl := resMat.data[i * resMat.stride + i]
 
-		resMat.data[i][i] = 1
+		resMat.data[i * resMat.stride + i] = 1
 		for j := i + 1; j < resMat.rows; j++ {
-			resMat.data[i][j] /= leadEl
+			resMat.data[i * resMat.stride + j] /= leadEl
 		}
 		resF[i] /= leadEl
 
 		for j := i + 1; j < resMat.rows; j++ {
-			el := resMat.data[j][i]
-			resMat.data[j][i] = 0
+			el := resMat.data[j * resMat.stride + i]
+			resMat.data[j * resMat.stride + i] = 0
 			for k := i + 1; k < resMat.cols; k++ {
-				resMat.data[j][k] -= resMat.data[i][k] * el
+				resMat.data[j * resMat.stride + k] -= resMat.data[i * resMat.stride + k] * el
 			}
 			resF[j] -= resF[i] * el
 		}
@@ -42,7 +41,7 @@ func backSubs(a *Matrix, f []float64) []float64 {
 	for i := a.rows - 2; i >= 0; i-- {
 		x[i] = f[i]
 		for j := a.cols - 1; j > i; j-- {
-			x[i] -= a.data[i][j] * x[j]
+			x[i] -= a.data[i * a.stride + j] * x[j]
 		}
 	}
 
@@ -52,7 +51,7 @@ func backSubs(a *Matrix, f []float64) []float64 {
 func forwElimLeadEl(a *Matrix, f []float64) (*Matrix, []float64, []int, error) {
 	n := a.rows
 
-	resMat := &Matrix{data: copy2dSlice(a.data), rows: n, cols: n}
+	resMat := a.Clone()
 	resF := make([]float64, n)
 	copy(resF, f)
 
@@ -63,12 +62,12 @@ func forwElimLeadEl(a *Matrix, f []float64) (*Matrix, []float64, []int, error) {
 
 	for i := 0; i < n; i++ {
 		// with leading col element
-		leadColElMod := math.Abs(resMat.data[i][i])
+		leadColElMod := math.Abs(resMat.data[i * resMat.stride + i])
 
 		k := i
 		for j := i + 1; j < n; j++ {
-			if math.Abs(resMat.data[j][i]) > leadColElMod {
-				leadColElMod = math.Abs(resMat.data[j][i])
+			if math.Abs(resMat.data[j * resMat.stride + i]) > leadColElMod {
+				leadColElMod = math.Abs(resMat.data[j * resMat.stride + i])
 				k = j
 			}
 		}
@@ -79,28 +78,28 @@ func forwElimLeadEl(a *Matrix, f []float64) (*Matrix, []float64, []int, error) {
 
 		if k != i {
 			for j := i; j < n; j++ {
-				resMat.data[i][j], resMat.data[k][j] = resMat.data[k][j], resMat.data[i][j]
+				resMat.data[i * resMat.stride + j], resMat.data[k * resMat.stride + j] = resMat.data[k * resMat.stride + j], resMat.data[i * resMat.stride + j]
 			}
 			resF[i], resF[k] = resF[k], resF[i]
 		}
 
-		leadEl := resMat.data[i][i]
+		leadEl := resMat.data[i * resMat.stride + i]
 
 		for j := i + 1; j < n; j++ {
-			c := -resMat.data[j][i] / leadEl
+			c := -resMat.data[j * resMat.stride + i] / leadEl
 			for l := i; l < n; l++ {
-				resMat.data[j][l] += resMat.data[i][l] * c
+				resMat.data[j * resMat.stride + l] += resMat.data[i * resMat.stride + l] * c
 			}
 			resF[j] += resF[i] * c
 		}
 
 		// with leading row element
-		leadRowElMod := math.Abs(resMat.data[i][i])
+		leadRowElMod := math.Abs(resMat.data[i * resMat.stride + i])
 
 		k = i
 		for j := i + 1; j < n; j++ {
-			if math.Abs(resMat.data[i][j]) > leadRowElMod {
-				leadRowElMod = math.Abs(resMat.data[i][j])
+			if math.Abs(resMat.data[i * resMat.stride + j]) > leadRowElMod {
+				leadRowElMod = math.Abs(resMat.data[i * resMat.stride + j])
 				k = j
 			}
 		}
@@ -111,17 +110,17 @@ func forwElimLeadEl(a *Matrix, f []float64) (*Matrix, []float64, []int, error) {
 
 		if k != i {
 			for j := 0; j < n; j++ {
-				resMat.data[j][i], resMat.data[j][k] = resMat.data[j][k], resMat.data[j][i]
+				resMat.data[j * resMat.stride + i], resMat.data[j * resMat.stride + k] = resMat.data[j * resMat.stride + k], resMat.data[j * resMat.stride + i]
 			}
 		}
 		idxs[i], idxs[k] = idxs[k], idxs[i]
 
-		leadEl = resMat.data[i][i]
+		leadEl = resMat.data[i * resMat.stride + i]
 
 		for j := i + 1; j < n; j++ {
-			c := -resMat.data[j][i] / leadEl
+			c := -resMat.data[j * resMat.stride + i] / leadEl
 			for l := i; l < n; l++ {
-				resMat.data[j][l] += resMat.data[i][l] * c
+				resMat.data[j * resMat.stride + l] += resMat.data[i * resMat.stride + l] * c
 			}
 			resF[j] += resF[i] * c
 		}
@@ -135,20 +134,50 @@ func backSubsLeadEl(a *Matrix, f []float64, idxs []int) []float64 {
 
 	x := make([]float64, n)
 
-	x[idxs[n - 1]] = f[n - 1] / a.data[n - 1][n - 1]
+	x[idxs[n - 1]] = f[n - 1] / a.data[(n - 1) * a.stride + n - 1]
 
 	for i := n - 2; i >= 0; i-- {
 		x[idxs[i]] = f[i]
 		for j := n - 1; j > i; j-- {
-			x[idxs[i]] -= a.data[i][j] * x[idxs[j]]
+			x[idxs[i]] -= a.data[i * a.stride + j] * x[idxs[j]]
 		}
-		x[idxs[i]] /= a.data[i][i]
+		x[idxs[i]] /= a.data[i * a.stride + i]
 	}
 
 	return x
 }
 
-func Gauss(a *Matrix, f []float64) ([]float64, error) {
+// sparse matrices are eliminated in sparse form, other accessors are densified
+func Gauss(a ElementAccessor, f []float64) ([]float64, error) {
+	switch m := a.(type) {
+	case *Matrix:
+		return gaussDense(m, f)
+	case *CSR:
+		if err := checkOperator(m, f); err != nil {
+			return nil, err
+		}
+		return sparseGauss(m, f)
+	case *CSC:
+		if err := checkOperator(m, f); err != nil {
+			return nil, err
+		}
+		return sparseGauss(m.ToCSR(), f)
+	case *Tridiagonal:
+		return m.Solve(f)
+	case *Banded:
+		if err := checkOperator(m, f); err != nil {
+			return nil, err
+		}
+		lu, err := FactorizeBandedLU(m)
+		if err != nil {
+			return nil, err
+		}
+		return lu.Solve(f)
+	}
+	return gaussDense(denseOf(a), f)
+}
+
+func gaussDense(a *Matrix, f []float64) ([]float64, error) {
 	if a.rows != len(f) {
 		return nil, errors.New("matrix and free element dims don't match")
 	}
@@ -231,233 +260,411 @@ func normVec(v []float64) float64 {
 	return max
 }
 
-func Jacobi(a *Matrix, f []float64) ([]float64, error) {
-	n := len(f)
-	if a.rows != n {
-		return nil, errors.New("matrix and free element dims don't match")
-	}
-	if a.rows == 0 || n == 0 {
-		return nil, errors.New("matrix or free element is empty")
+func Jacobi(a ElementAccessor, f []float64, opts *SolveOptions) (*SolveResult, error) {
+	return JacobiCtx(context.Background(), a, f, opts)
+}
+
+func JacobiCtx(ctx context.Context, a ElementAccessor, f []float64, opts *SolveOptions) (*SolveResult, error) {
+	a = byRows(a)
+	if err := checkOperator(a, f); err != nil {
+		return nil, err
 	}
-	if !a.IsDiagDominant() {
+	if !isDiagDominant(a) {
 		return nil, errors.New("matrix isn't diagonally dominant")
 	}
+	o, err := opts.withDefaults(len(f))
+	if err != nil {
+		return nil, err
+	}
 
-	x, xPrev := make([]float64, n), make([]float64, n)
-
-	//iter := 0
-	for {
-		//iter++
+	n := len(f)
+	xPrev, row := o.Workspace.vec(n), o.Workspace.vec(n)
+	defer releaseVecs(o.Workspace, xPrev, row)
+	return iterate(ctx, AsOperator(a), f, o, func(x []float64) {
+		copy(xPrev, x)
 		for i := 0; i < n; i++ {
-			var sum float64
-			for j := 0; j < n; j++ {
-				if j != i {
-					sum += a.data[i][j] * xPrev[j]
-				}
-			}
-			x[i] = (f[i] - sum) / a.data[i][i]
-		}
-		sub, _ := VecsSub(x, xPrev)
-		if VecNorm(sub, InfinityNorm) <= Epsilon {
-			//fmt.Println("iterations:", iter)
-			return x, nil
+			sum, aii := rowSplitDot(a, i, xPrev, row)
+			x[i] = (f[i] - sum) / aii
 		}
-		copy(xPrev, x)
-	}
+	})
 }
 
-func Seidel(a *Matrix, f []float64) ([]float64, error) {
-	n := len(f)
-	if a.rows != n {
-		return nil, errors.New("matrix and free element dims don't match")
-	}
-	if a.rows == 0 || n == 0 {
-		return nil, errors.New("matrix or free element is empty")
+func Seidel(a ElementAccessor, f []float64, opts *SolveOptions) (*SolveResult, error) {
+	return SeidelCtx(context.Background(), a, f, opts)
+}
+
+func SeidelCtx(ctx context.Context, a ElementAccessor, f []float64, opts *SolveOptions) (*SolveResult, error) {
+	a = byRows(a)
+	if err := checkOperator(a, f); err != nil {
+		return nil, err
 	}
-	if !a.IsDiagDominant() {
+	if !isDiagDominant(a) {
 		return nil, errors.New("matrix isn't diagonally dominant")
 	}
+	o, err := opts.withDefaults(len(f))
+	if err != nil {
+		return nil, err
+	}
 
-	x, xPrev := make([]float64, n), make([]float64, n)
-
-	//iter := 0
-	for {
-		//iter++
+	n := len(f)
+	row := o.Workspace.vec(n)
+	defer o.Workspace.release(row)
+	return iterate(ctx, AsOperator(a), f, o, func(x []float64) {
 		for i := 0; i < n; i++ {
-			var sum float64
-			for j := 0; j < n; j++ {
-				if j != i {
-					sum += a.data[i][j] * x[j]
-				}
-			}
-			x[i] = (f[i] - sum) / a.data[i][i]
-		}
-		sub, _ := VecsSub(x, xPrev)
-		if VecNorm(sub, InfinityNorm) <= Epsilon {
-			//fmt.Println("iterations:", iter)
-			return x, nil
+			sum, aii := rowSplitDot(a, i, x, row)
+			x[i] = (f[i] - sum) / aii
 		}
-		copy(xPrev, x)
+	})
+}
+
+const (
+	// an odd last row or column is split off and handled by Mul, so
+	// nothing is padded; the zero value, so the default
+	DynamicPeeling = iota
+	// every dimension is padded with zeros to a power of two
+	PowerOfTwoPadding
+)
+
+const (
+	strassCutoff   = 64
+	strassParDepth = 3
+	// smaller products run sequentially whatever the depth
+	strassParMin = 128
+)
+
+type StrassenOptions struct {
+	// products with a dimension at most Cutoff are left to Mul
+	Cutoff int
+	Padding int
+	// recursion levels running their seven products on Executor,
+	// 0 keeps the whole recursion in the calling goroutine
+	MaxParDepth int
+	// nil means the default executor
+	Executor *Executor
+	// temporaries come from Workspace, nil means a fresh one per call
+	Workspace *Workspace
+	// the Winograd variant needs 15 block additions instead of 18
+	Winograd bool
+}
+
+func (o *StrassenOptions) withDefaults() (*StrassenOptions, error) {
+	res := StrassenOptions{}
+	if o != nil {
+		res = *o
 	}
+	if res.Cutoff < 0 || res.MaxParDepth < 0 {
+		return nil, errors.New("cutoff or parallel depth is negative")
+	}
+	if res.Padding != PowerOfTwoPadding && res.Padding != DynamicPeeling {
+		return nil, errors.New("unknown padding strategy")
+	}
+	if res.Cutoff == 0 {
+		res.Cutoff = strassCutoff
+	}
+	if res.Executor == nil {
+		res.Executor = DefaultExecutor()
+	}
+	return &res, nil
 }
 
 func Strassen(a, b *Matrix, parallel bool) (*Matrix, error) {
+	return StrassenCtx(context.Background(), a, b, parallel)
+}
+
+func StrassenCtx(ctx context.Context, a, b *Matrix, parallel bool) (*Matrix, error) {
+	opts := &StrassenOptions{}
+	if parallel {
+		opts.MaxParDepth = strassParDepth
+	}
+	return StrassenWithOptionsCtx(ctx, a, b, opts)
+}
+
+func StrassenWithOptions(a, b *Matrix, opts *StrassenOptions) (*Matrix, error) {
+	return StrassenWithOptionsCtx(context.Background(), a, b, opts)
+}
+
+func StrassenWithOptionsCtx(ctx context.Context, a, b *Matrix, opts *StrassenOptions) (*Matrix, error) {
 	if a.cols != b.rows {
 		return nil, errors.New("matrix dims don't match")
 	}
-
-	adj := false
-	aData, bData := copy2dSlice(a.data), copy2dSlice(b.data)
-	if !a.IsSquare() || !b.IsSquare() || !isPowerOfTwo(a.rows) {
-		aData, bData = complementMatrixes(aData, bData, a.cols, b.cols)
-		adj = true
+	o, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
 	}
 
-	var resData [][]float64
-	if parallel {
-		var wg sync.WaitGroup
-		wg.Add(1)
-		resData = strassRecPar(aData, bData, 64, &wg)
-	} else {
-		resData = strassRec(aData, bData, 64)
+	// the operands are only read, so they are copied only when padded
+	aPad, bPad := a, b
+	if o.Padding == PowerOfTwoPadding {
+		m, k, n := nearestPowerOfTwo(a.rows), nearestPowerOfTwo(a.cols), nearestPowerOfTwo(b.cols)
+		if m != a.rows || k != a.cols {
+			aPad = padMat(a, m, k)
+		}
+		if k != b.rows || n != b.cols {
+			bPad = padMat(b, k, n)
+		}
 	}
 
-	if adj {
-		resData = adjustMatrix(resData, a.rows, b.cols)
+	ws := o.Workspace
+	if ws == nil {
+		ws = NewWorkspace()
+	}
+	res := newMat(aPad.rows, bPad.cols)
+	s := &strassen{ctx, o, ws}
+	if !s.mul(res, aPad, bPad, 0) {
+		return nil, ctx.Err()
 	}
 
-	return &Matrix{resData, a.rows, a.cols}, nil
+	return res.view(0, 0, a.rows, b.cols), nil
 }
 
-func complementMatrixes(a, b [][]float64, aC, bC int) ([][]float64, [][]float64) {
-	aR := len(a)
-	bR := len(b)
-	n := nearestPowerOfTwo(intMax4(aR, aC, bR, bC))
+func padMat(m *Matrix, rows, cols int) *Matrix {
+	res := newMat(rows, cols)
+	_ = res.view(0, 0, m.rows, m.cols).CopyFrom(m)
+	return res
+}
 
-	aNew := init2dSlice(n, n)
-	bNew := init2dSlice(n, n)
-	for i := 0; i < aR; i++ {
-		copy(aNew[i], a[i])
-	}
-	for i := 0; i < bR; i++ {
-		copy(bNew[i], b[i])
-	}
+type strassen struct {
+	ctx  context.Context
+	opts *StrassenOptions
+	ws   *Workspace
+}
 
-	return aNew, bNew
+// the quadrants of a and b, the operands and products of one recursion
+// step; the quadrants are views and the sums and products come from the
+// workspace
+type strassStep struct {
+	aq, bq, cq [4]*Matrix
+	as, bs, p  [7]*Matrix
+	sums       [10]*Matrix
+	nSums      int
 }
 
-func adjustMatrix(m [][]float64, rows, cols int) [][]float64 {
-	m = m[ : rows]
-	for i := 0; i < rows; i++ {
-		m[i] = m[i][ : cols]
-	}
-	return m
+func (s *strassen) quadrants(m *Matrix, q *[4]*Matrix) {
+	h, w := m.rows / 2, m.cols / 2
+	q[0], q[1] = s.ws.view(m, 0, 0, h, w), s.ws.view(m, 0, w, h, w)
+	q[2], q[3] = s.ws.view(m, h, 0, h, w), s.ws.view(m, h, w, h, w)
 }
 
-func divideSlice(a [][]float64, m int) ([][]float64, [][]float64, [][]float64, [][]float64) {
-	a11 := copy2dSlice(a[ : m])
-	a12 := copy2dSlice(a[ : m])
-	a21 := copy2dSlice(a[m : ])
-	a22 := copy2dSlice(a[m : ])
-	for i := 0; i < m; i++ {
-		a11[i] = a11[i][ : m]
-		a12[i] = a12[i][m : ]
-		a21[i] = a21[i][ : m]
-		a22[i] = a22[i][m : ]
-	}
-	return a11, a12, a21, a22
+func (s *strassen) sum(st *strassStep, a, b *Matrix) *Matrix {
+	res := matsSumTo(s.ws.mat(a.rows, a.cols), a, b)
+	st.sums[st.nSums] = res
+	st.nSums++
+	return res
 }
 
-func buildSlice(a11, a12, a21, a22 [][]float64) [][]float64 {
-	m := len(a11)
-	n := m * 2
-	a := init2dSlice(n, n)
+func (s *strassen) sub(st *strassStep, a, b *Matrix) *Matrix {
+	res := matsSubTo(s.ws.mat(a.rows, a.cols), a, b)
+	st.sums[st.nSums] = res
+	st.nSums++
+	return res
+}
 
-	for i := 0; i < m; i ++ {
-		for j := 0; j < m; j++ {
-			a[i][j] = a11[i][j]
-		}
+func (s *strassen) release(st *strassStep) {
+	for k := 0; k < 4; k++ {
+		s.ws.releaseMat(st.aq[k], true)
+		s.ws.releaseMat(st.bq[k], true)
+		s.ws.releaseMat(st.cq[k], true)
 	}
-	for i := 0; i < m; i ++ {
-		for j := 0; j < m; j++ {
-			a[i][m + j] = a12[i][j]
-		}
+	for k := 0; k < st.nSums; k++ {
+		s.ws.releaseMat(st.sums[k], false)
 	}
-	for i := 0; i < m; i ++ {
-		for j := 0; j < m; j++ {
-			a[m + i][j] = a21[i][j]
-		}
+	for k := range st.p {
+		s.ws.releaseMat(st.p[k], false)
 	}
-	for i := 0; i < m; i ++ {
-		for j := 0; j < m; j++ {
-			a[m + i][m + j] = a22[i][j]
-		}
+}
+
+// writes a * b to c, false means ctx was cancelled
+func (s *strassen) mul(c, a, b *Matrix, depth int) bool {
+	if s.ctx.Err() != nil {
+		return false
+	}
+	m, k, n := a.rows, a.cols, b.cols
+	if intMin(m, intMin(k, n)) <= s.opts.Cutoff {
+		matMulTo(c, a, b)
+		return true
+	}
+	if m % 2 != 0 || k % 2 != 0 || n % 2 != 0 {
+		return s.peel(c, a, b, depth)
 	}
 
-	return a
-}
+	var st strassStep
+	defer s.release(&st)
+	s.quadrants(a, &st.aq)
+	s.quadrants(b, &st.bq)
+	s.quadrants(c, &st.cq)
+	if s.opts.Winograd {
+		s.winogradOperands(&st)
+	} else {
+		s.strassOperands(&st)
+	}
+	for i := range st.p {
+		st.p[i] = s.ws.mat(m / 2, n / 2)
+	}
+
+	if depth < s.opts.MaxParDepth && m / 2 > strassParMin {
+		// copies, so that only the parallel steps put their operands on the heap
+		var ok [7]bool
+		as, bs, p := st.as, st.bs, st.p
+		s.opts.Executor.ParallelFor(7, func(i int) {
+			ok[i] = s.mul(p[i], as[i], bs[i], depth + 1)
+		})
+		for _, okI := range ok {
+			if !okI {
+				return false
+			}
+		}
+	} else {
+		for i := range st.p {
+			if !s.mul(st.p[i], st.as[i], st.bs[i], depth + 1) {
+				return false
+			}
+		}
+	}
 
-func strassRec(a, b [][]float64, nMin int) [][]float64 {
-	n := len(a)
-	if n <= nMin {
-		c, _ := MatMul(&Matrix{a, n, n}, &Matrix{b, n, n})
-		return c.data
+	if s.opts.Winograd {
+		winogradCombine(st.cq, st.p)
+	} else {
+		strassCombine(st.cq, st.p)
 	}
 
-	m := n / 2
+	return true
+}
 
-	a11, a12, a21, a22 := divideSlice(a, m)
-	b11, b12, b21, b22 := divideSlice(b, m)
+// dynamic peeling: the even leading block goes on with the recursion,
+// the odd last row, column and the rank one update go to the kernel
+func (s *strassen) peel(c, a, b *Matrix, depth int) bool {
+	m, k, n := a.rows &^ 1, a.cols &^ 1, b.cols &^ 1
 
-	p1 := strassRec(slicesSum(a11, a22), slicesSum(b11, b22), nMin)
-	p2 := strassRec(slicesSum(a21, a22), b11, nMin)
-	p3 := strassRec(a11, slicesSub(b12, b22), nMin)
-	p4 := strassRec(a22, slicesSub(b21, b11), nMin)
-	p5 := strassRec(slicesSum(a11, a12), b22, nMin)
-	p6 := strassRec(slicesSub(a21, a11), slicesSum(b11, b12), nMin)
-	p7 := strassRec(slicesSub(a12, a22), slicesSum(b21, b22), nMin)
+	c11, a11, b11 := s.ws.view(c, 0, 0, m, n), s.ws.view(a, 0, 0, m, k), s.ws.view(b, 0, 0, k, n)
+	ok := s.mul(c11, a11, b11, depth)
+	s.ws.releaseMat(c11, true)
+	s.ws.releaseMat(a11, true)
+	s.ws.releaseMat(b11, true)
+	if !ok {
+		return false
+	}
 
-	c11 := slicesSum(slicesSub(slicesSum(p1, p4), p5), p7)
-	c12 := slicesSum(p3, p5)
-	c21 := slicesSum(p2, p4)
-	c22 := slicesSum(slicesSub(slicesSum(p1, p3), p2), p6)
-	c := buildSlice(c11, c12, c21, c22)
+	if k < a.cols {
+		for i := 0; i < m; i++ {
+			aik, bk, ci := a.data[i * a.stride + k], b.row(k), c.row(i)
+			for j := 0; j < n; j++ {
+				ci[j] += aik * bk[j]
+			}
+		}
+	}
+	if n < b.cols {
+		cn, an, bn := s.ws.view(c, 0, n, m, 1), s.ws.view(a, 0, 0, m, a.cols), s.ws.view(b, 0, n, b.rows, 1)
+		matMulTo(cn, an, bn)
+		s.ws.releaseMat(cn, true)
+		s.ws.releaseMat(an, true)
+		s.ws.releaseMat(bn, true)
+	}
+	if m < a.rows {
+		cm, am := s.ws.view(c, m, 0, 1, b.cols), s.ws.view(a, m, 0, 1, a.cols)
+		matMulTo(cm, am, b)
+		s.ws.releaseMat(cm, true)
+		s.ws.releaseMat(am, true)
+	}
 
-	return c
+	return true
 }
 
-func strassRecPar(a, b [][]float64, nMin int, wg *sync.WaitGroup) [][]float64 {
-	defer wg.Done()
-	n := len(a)
-	if n <= nMin {
-		c, _ := MatMul(&Matrix{a, n, n}, &Matrix{b, n, n})
-		return c.data
+func matsSumTo(dst, a, b *Matrix) *Matrix {
+	for i := 0; i < dst.rows; i++ {
+		di, ai, bi := dst.row(i), a.row(i), b.row(i)
+		for j := range di {
+			di[j] = ai[j] + bi[j]
+		}
 	}
+	return dst
+}
 
-	m := n / 2
+func matsSubTo(dst, a, b *Matrix) *Matrix {
+	for i := 0; i < dst.rows; i++ {
+		di, ai, bi := dst.row(i), a.row(i), b.row(i)
+		for j := range di {
+			di[j] = ai[j] - bi[j]
+		}
+	}
+	return dst
+}
 
-	a11, a12, a21, a22 := divideSlice(a, m)
-	b11, b12, b21, b22 := divideSlice(b, m)
+// operands of the seven products, the quadrants are used in place
+func (s *strassen) strassOperands(st *strassStep) {
+	a11, a12, a21, a22 := st.aq[0], st.aq[1], st.aq[2], st.aq[3]
+	b11, b12, b21, b22 := st.bq[0], st.bq[1], st.bq[2], st.bq[3]
+
+	st.as = [7]*Matrix{
+		s.sum(st, a11, a22),
+		s.sum(st, a21, a22),
+		a11,
+		a22,
+		s.sum(st, a11, a12),
+		s.sub(st, a21, a11),
+		s.sub(st, a12, a22),
+	}
+	st.bs = [7]*Matrix{
+		s.sum(st, b11, b22),
+		b11,
+		s.sub(st, b12, b22),
+		s.sub(st, b21, b11),
+		b22,
+		s.sum(st, b11, b12),
+		s.sum(st, b21, b22),
+	}
+}
 
-	var wg2 sync.WaitGroup
-	wg2.Add(7)
-	var p1, p2, p3, p4, p5, p6, p7 [][]float64
-	go func() { p1 = strassRecPar(slicesSum(a11, a22), slicesSum(b11, b22), nMin, &wg2) }()
-	go func() { p2 = strassRecPar(slicesSum(a21, a22), b11, nMin, &wg2) }()
-	go func() { p3 = strassRecPar(a11, slicesSub(b12, b22), nMin, &wg2) }()
-	go func() { p4 = strassRecPar(a22, slicesSub(b21, b11), nMin, &wg2) }()
-	go func() { p5 = strassRecPar(slicesSum(a11, a12), b22, nMin, &wg2) }()
-	go func() { p6 = strassRecPar(slicesSub(a21, a11), slicesSum(b11, b12), nMin, &wg2) }()
-	go func() { p7 = strassRecPar(slicesSub(a12, a22), slicesSum(b21, b22), nMin, &wg2) }()
-	wg2.Wait()
+// c11 = p1 + p4 - p5 + p7, c12 = p3 + p5, c21 = p2 + p4,
+// c22 = p1 - p2 + p3 + p6
+func strassCombine(c [4]*Matrix, p [7]*Matrix) {
+	c11, c12, c21, c22 := c[0], c[1], c[2], c[3]
+	for i := 0; i < c11.rows; i++ {
+		r11, r12, r21, r22 := c11.row(i), c12.row(i), c21.row(i), c22.row(i)
+		p1, p2, p3, p4, p5, p6, p7 := p[0].row(i), p[1].row(i), p[2].row(i), p[3].row(i), p[4].row(i), p[5].row(i), p[6].row(i)
+		for j := range r11 {
+			r11[j] = p1[j] + p4[j] - p5[j] + p7[j]
+			r12[j] = p3[j] + p5[j]
+			r21[j] = p2[j] + p4[j]
+			r22[j] = p1[j] - p2[j] + p3[j] + p6[j]
+		}
+	}
+}
 
-	c11 := slicesSum(slicesSub(slicesSum(p1, p4), p5), p7)
-	c12 := slicesSum(p3, p5)
-	c21 := slicesSum(p2, p4)
-	c22 := slicesSum(slicesSub(slicesSum(p1, p3), p2), p6)
-	c := buildSlice(c11, c12, c21, c22)
+// s1 = a21 + a22, s2 = s1 - a11, s3 = a11 - a21, s4 = a12 - s2,
+// t1 = b12 - b11, t2 = b22 - t1, t3 = b22 - b12, t4 = t2 - b21
+func (s *strassen) winogradOperands(st *strassStep) {
+	a11, a12, a21, a22 := st.aq[0], st.aq[1], st.aq[2], st.aq[3]
+	b11, b12, b21, b22 := st.bq[0], st.bq[1], st.bq[2], st.bq[3]
+
+	s1 := s.sum(st, a21, a22)
+	s2 := s.sub(st, s1, a11)
+	s3 := s.sub(st, a11, a21)
+	s4 := s.sub(st, a12, s2)
+	t1 := s.sub(st, b12, b11)
+	t2 := s.sub(st, b22, t1)
+	t3 := s.sub(st, b22, b12)
+	t4 := s.sub(st, t2, b21)
+
+	st.as = [7]*Matrix{a11, a12, s4, a22, s1, s2, s3}
+	st.bs = [7]*Matrix{b11, b21, b22, t4, t1, t2, t3}
+}
 
-	return c
+// u2 = p1 + p6, u3 = u2 + p7, u4 = u2 + p5,
+// c11 = p1 + p2, c12 = u4 + p3, c21 = u3 - p4, c22 = u3 + p5
+func winogradCombine(c [4]*Matrix, p [7]*Matrix) {
+	c11, c12, c21, c22 := c[0], c[1], c[2], c[3]
+	for i := 0; i < c11.rows; i++ {
+		r11, r12, r21, r22 := c11.row(i), c12.row(i), c21.row(i), c22.row(i)
+		p1, p2, p3, p4, p5, p6, p7 := p[0].row(i), p[1].row(i), p[2].row(i), p[3].row(i), p[4].row(i), p[5].row(i), p[6].row(i)
+		for j := range r11 {
+			u2 := p1[j] + p6[j]
+			u3 := u2 + p7[j]
+			r11[j] = p1[j] + p2[j]
+			r12[j] = u2 + p5[j] + p3[j]
+			r21[j] = u3 - p4[j]
+			r22[j] = u3 + p5[j]
+		}
+	}
 }
 
 func Cholesky(a *Matrix) (*Matrix, error) {
@@ -466,159 +673,131 @@ func Cholesky(a *Matrix) (*Matrix, error) {
 	}
 
 	n := a.rows
+	resData, err := choleskyDecomp(a.rowSlices())
+	if err != nil {
+		return nil, err
+	}
+
+	return matOfRows(resData, n, n), nil
+}
+
+func choleskyDecomp(data [][]float64) ([][]float64, error) {
+	n := len(data)
 	resData := init2dSlice(n, n)
 
 	for i := 0; i < n; i++ {
-		s := a.data[i][i]
-		for ip := 0; ip < i - 1; ip++ {
+		s := data[i][i]
+		for ip := 0; ip < i; ip++ {
 			s = s - resData[i][ip] * resData[i][ip]
 		}
+		if s <= 0 {
+			return nil, errors.New("matrix isn't positive definite")
+		}
 		resData[i][i] = math.Sqrt(s)
 		for j := i + 1; j < n; j++ {
-			s = a.data[j][i]
-			for ip := 0; ip < i - 1; ip++ {
+			s = data[j][i]
+			for ip := 0; ip < i; ip++ {
 				s = s - resData[i][ip] * resData[j][ip]
 			}
 			resData[j][i] = s / resData[i][i]
 		}
 	}
 
-	return &Matrix{resData, n, n}, nil
+	return resData, nil
 }
 
 func MinMaxEigenvalues(m *Matrix) (float64, float64, error) {
-	n := m.rows
-	var mVec []float64
-	for i := 0; i < n; i++ {
-		mVec = append(mVec, m.data[i]...)
-	}
-
-	gonumM := mat.NewSymDense(n, mVec)
-
-	var eig mat.EigenSym
-	ok := eig.Factorize(gonumM, false)
-	if !ok {
-		return -1, -1, errors.New("symmetric eigendecomposition failed")
+	vals, _, err := EigenSym(m, false)
+	if err != nil {
+		return -1, -1, err
 	}
-	vals := eig.Values(nil)
 
-	min, max := findMinMax(vals)
-
-	return min, max, nil
+	return vals[0], vals[len(vals) - 1], nil
 }
 
-func MyMinMaxEigenvalues(a *Matrix) (float64, float64, error) {
-	n := a.rows
-
-	x := make([]float64, n)
-	xPrev := make([]float64, n)
-	for i, _ := range xPrev {
-		xPrev[i] = 1
-	}
-	xPrev = VecConstMul(xPrev, VecNorm(xPrev, EuclideanNorm))
-
-	var lMax float64
-	//iter := 0
-	for {
-		//iter++
-		y, _ := MatVecMul(a, xPrev)
-		x = VecConstMul(y, 1 / VecNorm(y, EuclideanNorm))
-		c, _ := MatVecMul(a, x)
-		lMax, _ = ScalarProd(c, x)
+func MyMinMaxEigenvalues(a LinearOperator) (float64, float64, error) {
+	return MyMinMaxEigenvaluesCtx(context.Background(), a)
+}
 
-		sub, _ := VecsSub(x, xPrev)
-		if VecNorm(sub, EuclideanNorm) <= 1e-1 {
-			break
+// on cancellation the current estimates are returned along with ctx.Err()
+func MyMinMaxEigenvaluesCtx(ctx context.Context, a LinearOperator) (float64, float64, error) {
+	dominant, err := PowerIterationCtx(ctx, a, nil)
+	if err != nil {
+		if ctx.Err() != nil && len(dominant) > 0 {
+			return dominant[0].Value, dominant[0].Value, err
 		}
-		copy(xPrev, x)
-	}
-
-	e, _ := IdentityMat(n)
-	lE := MatConstMul(e, lMax)
-	b, _ := MatsSub(a, lE)
-	x = make([]float64, n)
-	for i, _ := range xPrev {
-		xPrev[i] = 1
+		return 0, 0, err
 	}
-	xPrev = VecConstMul(xPrev, VecNorm(xPrev, EuclideanNorm))
-
-	var lMin float64
-	for {
-		//iter++
-		y, _ := MatVecMul(b, xPrev)
-		x = VecConstMul(y, 1 / VecNorm(y, EuclideanNorm))
-		c, _ := MatVecMul(b, x)
-		lMin, _ = ScalarProd(c, x)
+	l1 := dominant[0].Value
 
-		sub, _ := VecsSum(x, xPrev)
-		if VecNorm(sub, EuclideanNorm) <= 1e-1 {
-			//fmt.Println(iter)
-			break
+	// with the shift the iteration picks the eigenvalue farthest from l1
+	other, err := PowerIterationCtx(ctx, a, &PowerOptions{Shift: l1})
+	if err != nil {
+		if ctx.Err() != nil && len(other) > 0 {
+			return math.Min(l1, other[0].Value), math.Max(l1, other[0].Value), err
 		}
-		copy(xPrev, x)
+		return 0, 0, err
 	}
+	l2 := other[0].Value
+
+	return math.Min(l1, l2), math.Max(l1, l2), nil
+}
 
-	return lMax + lMin, lMax, nil
+func FixedPointIteration(a ElementAccessor, f []float64, opts *SolveOptions) (*SolveResult, error) {
+	return FixedPointIterationCtx(context.Background(), a, f, opts)
 }
 
-func FixedPointIteration(a *Matrix, f []float64) ([]float64, error) {
-	if !a.IsSymmetric() || !a.IsDiagDominant() {
+// t = 2 / (λmin + λmax), the optimal step for symmetric positive definite a
+func FixedPointIterationCtx(ctx context.Context, a ElementAccessor, f []float64, opts *SolveOptions) (*SolveResult, error) {
+	if err := checkOperator(a, f); err != nil {
+		return nil, err
+	}
+	if !isSymmetric(a) || !isDiagDominant(a) {
 		return nil, errors.New("matrix isn't symmetric and diagonally dominant")
 	}
-	n := a.rows
+	op := AsOperator(a)
 
-	minEigenVal, maxEigenVal, err := MyMinMaxEigenvalues(a)
+	minEigenVal, maxEigenVal, err := MyMinMaxEigenvaluesCtx(ctx, op)
 	if err != nil {
 		return nil, err
 	}
 
 	t := 2 / (minEigenVal + maxEigenVal)
 
-	x, xPrev := make([]float64, n), make([]float64, n)
-
-	e, _ := IdentityMat(n)
-	tA := MatConstMul(a, t)
-	p, _ := MatsSub(e, tA)
-	g := VecConstMul(f, t)
+	return fixedPoint(ctx, op, f, t, opts)
+}
 
-	//iter := 0
-	for {
-		//iter++
-		pxPrev, _ := MatVecMul(p, xPrev)
-		x, _ = VecsSum(pxPrev, g)
-		sub, _ := VecsSub(x, xPrev)
-		if VecNorm(sub, InfinityNorm) <= Epsilon {
-			//fmt.Println("iterations:", iter)
-			return x, nil
-		}
-		copy(xPrev, x)
-	}
+func FixedPointIterationWithT(a ElementAccessor, f []float64, t float64, opts *SolveOptions) (*SolveResult, error) {
+	return FixedPointIterationWithTCtx(context.Background(), a, f, t, opts)
 }
 
-func FixedPointIterationWithT(a *Matrix, f []float64, t float64) ([]float64, int, error) {
-	if !a.IsSymmetric() || !a.IsDiagDominant() {
-		return nil, -1, errors.New("matrix isn't symmetric and diagonally dominant")
+func FixedPointIterationWithTCtx(ctx context.Context, a ElementAccessor, f []float64, t float64, opts *SolveOptions) (*SolveResult, error) {
+	if err := checkOperator(a, f); err != nil {
+		return nil, err
+	}
+	if !isSymmetric(a) || !isDiagDominant(a) {
+		return nil, errors.New("matrix isn't symmetric and diagonally dominant")
 	}
-	n := a.rows
 
-	x, xPrev := make([]float64, n), make([]float64, n)
+	return fixedPoint(ctx, AsOperator(a), f, t, opts)
+}
 
-	e, _ := IdentityMat(n)
-	tA := MatConstMul(a, t)
-	p, _ := MatsSub(e, tA)
-	g := VecConstMul(f, t)
+// x = (E - tA) x + tf
+func fixedPoint(ctx context.Context, a LinearOperator, f []float64, t float64, opts *SolveOptions) (*SolveResult, error) {
+	n := len(f)
+	o, err := opts.withDefaults(n)
+	if err != nil {
+		return nil, err
+	}
 
-	iter := 0
-	for {
-		iter++
-		pxPrev, _ := MatVecMul(p, xPrev)
-		x, _ = VecsSum(pxPrev, g)
-		sub, _ := VecsSub(x, xPrev)
-		if VecNorm(sub, InfinityNorm) <= Epsilon {
-			return x, iter, nil
+	ax := o.Workspace.vec(n)
+	defer o.Workspace.release(ax)
+	return iterate(ctx, a, f, o, func(x []float64) {
+		a.MulVec(ax, x)
+		for i := 0; i < n; i++ {
+			x[i] += t * (f[i] - ax[i])
 		}
-		copy(xPrev, x)
-	}
+	})
 }
 
 func LUSolve(l, u *Matrix, f []float64) ([]float64, error) {
@@ -641,16 +820,16 @@ func LUSolve(l, u *Matrix, f []float64) ([]float64, error) {
 	for i := 0; i < n; i++ {
 		var sum float64
 		for k := 0; k <= i - 1; k++ {
-			sum += l.data[i][k] * y[k]
+			sum += l.data[i * l.stride + k] * y[k]
 		}
 		y[i] = f[i] - sum
 	}
 	for i := n - 1; i >= 0; i-- {
 		var sum float64
 		for k := i + 1; k < n; k++ {
-			sum += u.data[i][k] * x[k]
+			sum += u.data[i * u.stride + k] * x[k]
 		}
-		x[i] = (y[i] - sum) / u.data[i][i]
+		x[i] = (y[i] - sum) / u.data[i * u.stride + i]
 	}
 
 	return x, nil
@@ -1,6 +1,7 @@
 package algnum
 
 import (
+	"context"
 	"fmt"
 	"gonum.org/v1/gonum/mat"
 	"math"
@@ -34,7 +35,7 @@ func randData(rows, cols int, min, max int) [][]float64 {
 	}
 
 	for i := range res {
-		for j := range res {
+		for j := range res[i] {
 			res[i][j] = float64(rand.Intn(max - min) + min)
 		}
 	}
@@ -357,13 +358,13 @@ func TestJacobi(t *testing.T) {
 
 	expectedRes := []float64{-float64(14) / float64(19), float64(32) / float64(19), -float64(3) / float64(19)}
 
-	res, err := Jacobi(mat, f)
+	res, err := Jacobi(mat, f, nil)
 	if err != nil {
 		t.Fatal(err)
-	} else if !VectsEq(expectedRes, res, Epsilon) {
-		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes), VectToStr(res))
+	} else if !VectsEq(expectedRes, res.X, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes), VectToStr(res.X))
 	} else {
-		t.Log("jacobi works correct, input:\nA = ", mat.ToStr(), "\nf = ", VectToStr(f), "\nresult:", VectToStr(res))
+		t.Log("jacobi works correct, input:\nA = ", mat.ToStr(), "\nf = ", VectToStr(f), "\nresult:", VectToStr(res.X))
 	}
 
 	data2 := [][]float64{
@@ -376,30 +377,30 @@ func TestJacobi(t *testing.T) {
 
 	expectedRes2 := []float64{1, 1, 1}
 
-	res2, err := Jacobi(mat2, f2)
+	res2, err := Jacobi(mat2, f2, nil)
 	if err != nil {
 		t.Fatal(err)
-	} else if !VectsEq(expectedRes2, res2, Epsilon) {
-		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes2), VectToStr(res2))
+	} else if !VectsEq(expectedRes2, res2.X, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes2), VectToStr(res2.X))
 	} else {
-		t.Log("jacobi works correct, input:\nA = ", mat2.ToStr(), "\nf = ", VectToStr(f2), "\nresult:", VectToStr(res2))
+		t.Log("jacobi works correct, input:\nA = ", mat2.ToStr(), "\nf = ", VectToStr(f2), "\nresult:", VectToStr(res2.X))
 	}
 
 	dataN := randDiagDominantData(1000, 1000, 1, 100)
 	matN, _ := InitMat(dataN)
 	fN := randFree(1000, 1, 1000)
 
-	resN, err := Jacobi(matN, fN)
+	resN, err := Jacobi(matN, fN, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	check, err := MatVecMul(matN, resN)
+	check, err := MatVecMul(matN, resN.X)
 	if err != nil {
 		t.Fatal(err)
 	} else if !VectsEq(fN, check, 1e-1) {
-		t.Fatalf("result is wrong, input:\nA = %s\nf = %s\nres = %s\ncheck:%s", matN.ToStr(), VectToStr(fN), VectToStr(resN), VectToStr(check))
+		t.Fatalf("result is wrong, input:\nA = %s\nf = %s\nres = %s\ncheck:%s", matN.ToStr(), VectToStr(fN), VectToStr(resN.X), VectToStr(check))
 	} else {
-		t.Log("jacobi works correct, input:\nA = ", matN.ToStr(), "\nf = ", VectToStr(fN), "\nresult:", VectToStr(resN), "\ncheck:", VectToStr(check))
+		t.Log("jacobi works correct, input:\nA = ", matN.ToStr(), "\nf = ", VectToStr(fN), "\nresult:", VectToStr(resN.X), "\ncheck:", VectToStr(check))
 	}
 }
 
@@ -414,13 +415,13 @@ func TestSeidel(t *testing.T) {
 
 	expectedRes := []float64{-float64(14) / float64(19), float64(32) / float64(19), -float64(3) / float64(19)}
 
-	res, err := Seidel(mat, f)
+	res, err := Seidel(mat, f, nil)
 	if err != nil {
 		t.Fatal(err)
-	} else if !VectsEq(expectedRes, res, Epsilon) {
-		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes), VectToStr(res))
+	} else if !VectsEq(expectedRes, res.X, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes), VectToStr(res.X))
 	} else {
-		t.Log("siedel works correct, input:\nA = ", mat.ToStr(), "\nf = ", VectToStr(f), "\nresult:", VectToStr(res))
+		t.Log("siedel works correct, input:\nA = ", mat.ToStr(), "\nf = ", VectToStr(f), "\nresult:", VectToStr(res.X))
 	}
 
 	data2 := [][]float64{
@@ -433,30 +434,30 @@ func TestSeidel(t *testing.T) {
 
 	expectedRes2 := []float64{1, 1, 1}
 
-	res2, err := Seidel(mat2, f2)
+	res2, err := Seidel(mat2, f2, nil)
 	if err != nil {
 		t.Fatal(err)
-	} else if !VectsEq(expectedRes2, res2, Epsilon) {
-		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes2), VectToStr(res2))
+	} else if !VectsEq(expectedRes2, res2.X, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes2), VectToStr(res2.X))
 	} else {
-		t.Log("seidel works correct, input:\nA = ", mat2.ToStr(), "\nf = ", VectToStr(f2), "\nresult:", VectToStr(res2))
+		t.Log("seidel works correct, input:\nA = ", mat2.ToStr(), "\nf = ", VectToStr(f2), "\nresult:", VectToStr(res2.X))
 	}
 
 	dataN := randDiagDominantData(1000, 1000, 1, 100)
 	matN, _ := InitMat(dataN)
 	fN := randFree(1000, 1, 1000)
 
-	resN, err := Seidel(matN, fN)
+	resN, err := Seidel(matN, fN, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	check, err := MatVecMul(matN, resN)
+	check, err := MatVecMul(matN, resN.X)
 	if err != nil {
 		t.Fatal(err)
 	} else if !VectsEq(fN, check, 1e-2) {
-		t.Fatalf("result is wrong, input:\nA = %s\nf = %s\nres = %s\ncheck:%s", matN.ToStr(), VectToStr(fN), VectToStr(resN), VectToStr(check))
+		t.Fatalf("result is wrong, input:\nA = %s\nf = %s\nres = %s\ncheck:%s", matN.ToStr(), VectToStr(fN), VectToStr(resN.X), VectToStr(check))
 	} else {
-		t.Log("seidel works correct, input:\nA = ", matN.ToStr(), "\nf = ", VectToStr(fN), "\nresult:", VectToStr(resN), "\ncheck:", VectToStr(check))
+		t.Log("seidel works correct, input:\nA = ", matN.ToStr(), "\nf = ", VectToStr(fN), "\nresult:", VectToStr(resN.X), "\ncheck:", VectToStr(check))
 	}
 }
 
@@ -551,10 +552,12 @@ func TestFixedPointIteration(t *testing.T) {
 
 	expectedRes := []float64{-0.0952, 1.52, -0.238}
 
-	res, err := FixedPointIteration(mat, f)
+	sol, err := FixedPointIteration(mat, f, nil)
 	if err != nil {
 		t.Fatal(err)
-	} else if !VectsEq(expectedRes, res, 1e-2) {
+	}
+	res := sol.X
+	if !VectsEq(expectedRes, res, 1e-2) {
 		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes), VectToStr(res))
 	} else {
 		t.Log("fixed-point iteration works correct, input:\nA = ", mat.ToStr(), "\nf = ", VectToStr(f), "\nresult:", VectToStr(res))
@@ -564,10 +567,11 @@ func TestFixedPointIteration(t *testing.T) {
 	matN, _ := InitMat(dataN)
 	fN := randFree(1000, 1, 10)
 
-	resN, err := FixedPointIteration(matN, fN)
+	solN, err := FixedPointIteration(matN, fN, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	resN := solN.X
 	check, err := MatVecMul(matN, resN)
 	if err != nil {
 		t.Fatal(err)
@@ -639,4 +643,150 @@ func TestLUSolve(t *testing.T) {
 	sub, _ := VecsSub(resN, lapack)
 	n := VecNorm(sub, EuclideanNorm)
 	t.Logf("LU-solve norm: %0.15f", n)
-}
\ No newline at end of file
+}
+
+func TestStrassenCtx(t *testing.T) {
+	aData := randData(256, 256, 1, 10)
+	a, _ := InitMat(aData)
+	bData := randData(256, 256, 1, 10)
+	b, _ := InitMat(bData)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, parallel := range []bool{false, true} {
+		res, err := StrassenCtx(ctx, a, b, parallel)
+		if err != context.Canceled || res != nil {
+			t.Fatalf("expected canceled error, got %v", err)
+		}
+	}
+
+	res, err := StrassenCtx(context.Background(), a, b, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := MatMul(a, b)
+	if !MatsEq(expected, res, Epsilon) {
+		t.Fatal("result is wrong: strassen doesn't match matrix multiplication")
+	} else {
+		t.Log("strassen cancellation works correct")
+	}
+}
+
+func TestFixedPointIterationCtx(t *testing.T) {
+	data := [][]float64{
+		{3, 1, 1},
+		{1, 3, 2},
+		{1, 2, 4},
+	}
+	mat, _ := InitMat(data)
+	f := []float64{1, 4, 2}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := FixedPointIterationCtx(ctx, mat, f, nil); err != context.Canceled {
+		t.Fatalf("expected canceled error, got %v", err)
+	}
+	if _, _, err := MyMinMaxEigenvaluesCtx(ctx, mat); err != context.Canceled {
+		t.Fatalf("expected canceled error, got %v", err)
+	}
+	res, err := FixedPointIterationWithTCtx(ctx, mat, f, 0.2, nil)
+	if err != context.Canceled || len(res.X) != 3 {
+		t.Fatalf("expected canceled error with partial result, got %v", err)
+	} else {
+		t.Log("fixed-point iteration cancellation works correct")
+	}
+}
+
+func TestStrassenShapes(t *testing.T) {
+	for _, n := range []int{100, 128, 200} {
+		a, _ := InitMat(randData(n, n, 1, 10))
+		b, _ := InitMat(randData(n, n, 1, 10))
+		expected, _ := MatMul(a, b)
+		for _, parallel := range []bool{false, true} {
+			res, err := Strassen(a, b, parallel)
+			if err != nil {
+				t.Fatal(err)
+			} else if !MatsEq(expected, res, Epsilon) {
+				t.Fatalf("result is wrong: strassen doesn't match matrix multiplication for n = %d", n)
+			}
+		}
+	}
+	t.Log("strassen works correct")
+}
+
+func benchmarkStrassen(b *testing.B, n int, parallel bool) {
+	x, _ := InitMat(randData(n, n, 1, 10))
+	y, _ := InitMat(randData(n, n, 1, 10))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Strassen(x, y, parallel)
+	}
+}
+
+func BenchmarkStrassen256(b *testing.B)    { benchmarkStrassen(b, 256, false) }
+func BenchmarkStrassen512(b *testing.B)    { benchmarkStrassen(b, 512, false) }
+func BenchmarkStrassenPar512(b *testing.B) { benchmarkStrassen(b, 512, true) }
+
+func TestStrassenOptions(t *testing.T) {
+	for _, dims := range [][3]int{{37, 53, 29}, {64, 16, 100}, {101, 99, 77}, {5, 200, 3}} {
+		m, k, n := dims[0], dims[1], dims[2]
+		a, _ := InitMat(randData(m, k, -9, 10))
+		b, _ := InitMat(randData(k, n, -9, 10))
+		expected := gonumMul(a, b)
+
+		for _, padding := range []int{PowerOfTwoPadding, DynamicPeeling} {
+			for _, winograd := range []bool{false, true} {
+				for _, depth := range []int{0, 2} {
+					opts := &StrassenOptions{Cutoff: 4, Padding: padding, MaxParDepth: depth, Winograd: winograd}
+					res, err := StrassenWithOptions(a, b, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if r, c := res.Dims(); r != m || c != n {
+						t.Fatalf("result dims are %dx%d, expected %dx%d", r, c, m, n)
+					}
+					if !MatsEq(expected, res, 1e-8) {
+						t.Fatalf("result is wrong for %dx%d * %dx%d with %+v", m, k, k, n, *opts)
+					}
+				}
+			}
+		}
+	}
+
+	// big enough for the products to go to the executor
+	a, _ := InitMat(randData(300, 270, -9, 10))
+	b, _ := InitMat(randData(270, 290, -9, 10))
+	for _, winograd := range []bool{false, true} {
+		opts := &StrassenOptions{Cutoff: 16, MaxParDepth: 2, Winograd: winograd, Executor: NewExecutor(4)}
+		res, err := StrassenWithOptions(a, b, opts)
+		if err != nil {
+			t.Fatal(err)
+		} else if !MatsEq(gonumMul(a, b), res, 1e-8) {
+			t.Fatal("result is wrong for the parallel strassen")
+		}
+	}
+
+	// options without Padding peel instead of padding to 256
+	a, _ = InitMat(randData(129, 129, -9, 10))
+	res, err := StrassenWithOptions(a, a, &StrassenOptions{Cutoff: 32})
+	if err != nil {
+		t.Fatal(err)
+	} else if res.stride != 129 {
+		t.Fatalf("result of 129x129 product has stride %d, expected 129", res.stride)
+	} else if !MatsEq(gonumMul(a, a), res, 1e-8) {
+		t.Fatal("result is wrong for the default padding")
+	}
+
+	a, _ = InitMat(randData(4, 4, 1, 10))
+	if _, err := StrassenWithOptions(a, a, &StrassenOptions{Cutoff: -1}); err == nil {
+		t.Fatal("expected negative cutoff error")
+	}
+	if _, err := StrassenWithOptions(a, a, &StrassenOptions{Padding: 7}); err == nil {
+		t.Fatal("expected unknown padding error")
+	} else {
+		t.Log("strassen options work correct")
+	}
+}
@@ -0,0 +1,553 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+)
+
+// tridiagonal matrix: sub[i] = a[i + 1][i], diag[i] = a[i][i],
+// super[i] = a[i][i + 1]
+type Tridiagonal struct {
+	n     int
+	sub   []float64
+	diag  []float64
+	super []float64
+}
+
+// band matrix with kl subdiagonals and ku superdiagonals, row i keeps
+// a[i][i - kl : i + ku + 1] so a[i][j] is data[i][j - i + kl]
+type Banded struct {
+	n, kl, ku int
+	data      [][]float64
+}
+
+type BandedLUFactor struct {
+	// row i of U keeps u[i][i : i + w]
+	u [][]float64
+	// l[k][r] is the multiplier of row k + r + 1 on step k,
+	// piv[k] the row swapped with row k before it
+	l     [][]float64
+	piv   []int
+	sign  float64
+	n, w  int
+}
+
+type BandedCholeskyFactor struct {
+	// row i of L keeps l[i][i - k : i + 1]
+	l    [][]float64
+	n, k int
+}
+
+func InitTridiagonal(sub, diag, super []float64) (*Tridiagonal, error) {
+	n := len(diag)
+	if n == 0 {
+		return nil, errors.New("matrix is empty")
+	}
+	if len(sub) != n - 1 || len(super) != n - 1 {
+		return nil, errors.New("diagonals dims don't match")
+	}
+
+	res := &Tridiagonal{n, make([]float64, n - 1), make([]float64, n), make([]float64, n - 1)}
+	copy(res.sub, sub)
+	copy(res.diag, diag)
+	copy(res.super, super)
+	return res, nil
+}
+
+func (t *Tridiagonal) Dims() (int, int) {
+	return t.n, t.n
+}
+
+func (t *Tridiagonal) At(i, j int) float64 {
+	switch j - i {
+	case -1:
+		return t.sub[j]
+	case 0:
+		return t.diag[i]
+	case 1:
+		return t.super[i]
+	}
+	return 0
+}
+
+func (t *Tridiagonal) MulVec(dst, x []float64) {
+	tridiagMulVec(t.sub, t.diag, t.super, dst, x)
+}
+
+func (t *Tridiagonal) MulVecTrans(dst, x []float64) {
+	tridiagMulVec(t.super, t.diag, t.sub, dst, x)
+}
+
+func tridiagMulVec(sub, diag, super, dst, x []float64) {
+	n := len(diag)
+	for i := 0; i < n; i++ {
+		sum := diag[i] * x[i]
+		if i > 0 {
+			sum += sub[i - 1] * x[i - 1]
+		}
+		if i < n - 1 {
+			sum += super[i] * x[i + 1]
+		}
+		dst[i] = sum
+	}
+}
+
+func (t *Tridiagonal) IsDiagDominant() bool {
+	for i := 0; i < t.n; i++ {
+		var sum float64
+		if i > 0 {
+			sum += math.Abs(t.sub[i - 1])
+		}
+		if i < t.n - 1 {
+			sum += math.Abs(t.super[i])
+		}
+		if math.Abs(t.diag[i]) < sum {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Tridiagonal) IsSymmetric() bool {
+	for i := range t.sub {
+		if t.sub[i] != t.super[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Tridiagonal) ToBanded() *Banded {
+	b, _ := InitBanded(t.n, 1, 1)
+	for i := 0; i < t.n; i++ {
+		if i > 0 {
+			b.data[i][0] = t.sub[i - 1]
+		}
+		b.data[i][1] = t.diag[i]
+		if i < t.n - 1 {
+			b.data[i][2] = t.super[i]
+		}
+	}
+	return b
+}
+
+func (t *Tridiagonal) ToMat() *Matrix {
+	return t.ToBanded().ToMat()
+}
+
+// diagonally dominant systems are solved with the Thomas algorithm in O(n),
+// the others with the pivoted banded LU which is O(n) as well
+func (t *Tridiagonal) Solve(f []float64) ([]float64, error) {
+	if err := checkOperator(t, f); err != nil {
+		return nil, err
+	}
+
+	if !t.IsDiagDominant() {
+		lu, err := FactorizeBandedLU(t.ToBanded())
+		if err != nil {
+			return nil, err
+		}
+		return lu.Solve(f)
+	}
+
+	x := make([]float64, t.n)
+	if err := thomas(t.sub, t.diag, t.super, f, x, make([]float64, t.n)); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// solves the periodic system where additionally a[n - 1][0] = lower and
+// a[0][n - 1] = upper, as a rank one correction of the tridiagonal one
+// (Sherman-Morrison); the system has to be diagonally dominant
+func (t *Tridiagonal) SolveCyclic(f []float64, lower, upper float64) ([]float64, error) {
+	if err := checkOperator(t, f); err != nil {
+		return nil, err
+	}
+	n := t.n
+	if n < 3 {
+		return nil, errors.New("cyclic system needs at least 3 unknowns")
+	}
+	// Thomas without pivoting is only stable for diagonally dominant
+	// systems, the corners included
+	for i := 0; i < n; i++ {
+		sum := math.Abs(upper) + math.Abs(t.super[0])
+		if i == n - 1 {
+			sum = math.Abs(t.sub[n - 2]) + math.Abs(lower)
+		} else if i > 0 {
+			sum = math.Abs(t.sub[i - 1]) + math.Abs(t.super[i])
+		}
+		if math.Abs(t.diag[i]) < sum {
+			return nil, errors.New("cyclic system isn't diagonally dominant")
+		}
+	}
+
+	gamma := -t.diag[0]
+	if gamma == 0 {
+		gamma = -1
+	}
+	diag := make([]float64, n)
+	copy(diag, t.diag)
+	diag[0] -= gamma
+	diag[n - 1] -= lower * upper / gamma
+
+	x, z, u, work := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	u[0], u[n - 1] = gamma, lower
+	if err := thomas(t.sub, diag, t.super, f, x, work); err != nil {
+		return nil, err
+	}
+	if err := thomas(t.sub, diag, t.super, u, z, work); err != nil {
+		return nil, err
+	}
+
+	denom := 1 + z[0] + upper * z[n - 1] / gamma
+	if denom == 0 {
+		return nil, &SingularMatrixError{Rank: n - 1}
+	}
+	fact := (x[0] + upper * x[n - 1] / gamma) / denom
+	for i := range x {
+		x[i] -= fact * z[i]
+	}
+	return x, nil
+}
+
+// forward sweep without pivoting, work keeps the modified superdiagonal
+func thomas(sub, diag, super, f, dst, work []float64) error {
+	n := len(diag)
+	if diag[0] == 0 {
+		return &SingularMatrixError{Rank: 0}
+	}
+	if n > 1 {
+		work[0] = super[0] / diag[0]
+	}
+	dst[0] = f[0] / diag[0]
+	for i := 1; i < n; i++ {
+		den := diag[i] - sub[i - 1] * work[i - 1]
+		if den == 0 {
+			return &SingularMatrixError{Rank: i}
+		}
+		if i < n - 1 {
+			work[i] = super[i] / den
+		}
+		dst[i] = (f[i] - sub[i - 1] * dst[i - 1]) / den
+	}
+	for i := n - 2; i >= 0; i-- {
+		dst[i] -= work[i] * dst[i + 1]
+	}
+	return nil
+}
+
+func InitBanded(n, kl, ku int) (*Banded, error) {
+	if n <= 0 {
+		return nil, errors.New("wrong dimention")
+	}
+	if kl < 0 || ku < 0 {
+		return nil, errors.New("bandwidth is negative")
+	}
+	return &Banded{n, kl, ku, init2dSlice(n, kl + ku + 1)}, nil
+}
+
+// fails if m has nonzeros outside the band
+func MatToBanded(m *Matrix, kl, ku int) (*Banded, error) {
+	if !m.IsSquare() {
+		return nil, errors.New("matrix isn't square")
+	}
+	res, err := InitBanded(m.rows, kl, ku)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			if j - i >= -kl && j - i <= ku {
+				res.data[i][j - i + kl] = m.data[i * m.stride + j]
+			} else if m.data[i * m.stride + j] != 0 {
+				return nil, errors.New("matrix has nonzeros outside the band")
+			}
+		}
+	}
+	return res, nil
+}
+
+func (b *Banded) Dims() (int, int) {
+	return b.n, b.n
+}
+
+func (b *Banded) Bandwidth() (int, int) {
+	return b.kl, b.ku
+}
+
+func (b *Banded) inBand(i, j int) bool {
+	return j - i >= -b.kl && j - i <= b.ku
+}
+
+func (b *Banded) At(i, j int) float64 {
+	if !b.inBand(i, j) {
+		return 0
+	}
+	return b.data[i][j - i + b.kl]
+}
+
+func (b *Banded) Set(i, j int, v float64) error {
+	if i < 0 || i >= b.n || j < 0 || j >= b.n {
+		return errors.New("index out of range")
+	}
+	if !b.inBand(i, j) {
+		return errors.New("index is outside the band")
+	}
+	b.data[i][j - i + b.kl] = v
+	return nil
+}
+
+func (b *Banded) MulVec(dst, x []float64) {
+	for i := 0; i < b.n; i++ {
+		lo, hi := intMax(0, i - b.kl), intMin(b.n - 1, i + b.ku)
+		var sum float64
+		for j := lo; j <= hi; j++ {
+			sum += b.data[i][j - i + b.kl] * x[j]
+		}
+		dst[i] = sum
+	}
+}
+
+func (b *Banded) MulVecTrans(dst, x []float64) {
+	for j := range dst {
+		dst[j] = 0
+	}
+	for i := 0; i < b.n; i++ {
+		lo, hi := intMax(0, i - b.kl), intMin(b.n - 1, i + b.ku)
+		for j := lo; j <= hi; j++ {
+			dst[j] += b.data[i][j - i + b.kl] * x[i]
+		}
+	}
+}
+
+func (b *Banded) IsDiagDominant() bool {
+	for i := 0; i < b.n; i++ {
+		lo, hi := intMax(0, i - b.kl), intMin(b.n - 1, i + b.ku)
+		var sum float64
+		for j := lo; j <= hi; j++ {
+			if j != i {
+				sum += math.Abs(b.data[i][j - i + b.kl])
+			}
+		}
+		if math.Abs(b.data[i][b.kl]) < sum {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Banded) IsSymmetric() bool {
+	k := intMax(b.kl, b.ku)
+	for i := 0; i < b.n; i++ {
+		for j := i + 1; j <= intMin(b.n - 1, i + k); j++ {
+			if b.At(i, j) != b.At(j, i) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (b *Banded) ToMat() *Matrix {
+	res := newMat(b.n, b.n)
+	for i := 0; i < b.n; i++ {
+		lo, hi := intMax(0, i - b.kl), intMin(b.n - 1, i + b.ku)
+		for j := lo; j <= hi; j++ {
+			res.data[i * res.stride + j] = b.data[i][j - i + b.kl]
+		}
+	}
+	return res
+}
+
+// Gaussian elimination with partial pivoting, the row interchanges widen
+// the upper band of U to kl + ku
+func FactorizeBandedLU(b *Banded) (*BandedLUFactor, error) {
+	n, kl := b.n, b.kl
+	// working row i covers columns i - kl : i + kl + ku + 1, so that a
+	// pivot row swapped up from i + kl still fits
+	w := 2 * kl + b.ku + 1
+
+	ab := init2dSlice(n, w)
+	for i := 0; i < n; i++ {
+		copy(ab[i], b.data[i])
+	}
+	l := init2dSlice(n, kl)
+	piv := make([]int, n)
+	sign := float64(1)
+
+	for k := 0; k < n; k++ {
+		last, right := intMin(n - 1, k + kl), intMin(n - 1, k + kl + b.ku)
+
+		p := k
+		for i := k + 1; i <= last; i++ {
+			if math.Abs(ab[i][k - i + kl]) > math.Abs(ab[p][k - p + kl]) {
+				p = i
+			}
+		}
+		piv[k] = p
+		if ab[p][k - p + kl] == 0 {
+			return nil, &SingularMatrixError{Rank: k}
+		}
+		if p != k {
+			sign = -sign
+			for j := k; j <= right; j++ {
+				ab[k][j - k + kl], ab[p][j - p + kl] = ab[p][j - p + kl], ab[k][j - k + kl]
+			}
+		}
+
+		pivot := ab[k][kl]
+		for i := k + 1; i <= last; i++ {
+			m := ab[i][k - i + kl] / pivot
+			l[k][i - k - 1] = m
+			if m == 0 {
+				continue
+			}
+			for j := k + 1; j <= right; j++ {
+				ab[i][j - i + kl] -= m * ab[k][j - k + kl]
+			}
+		}
+	}
+
+	u := make([][]float64, n)
+	for i := range u {
+		u[i] = ab[i][kl : ]
+	}
+	return &BandedLUFactor{u: u, l: l, piv: piv, sign: sign, n: n, w: w - kl}, nil
+}
+
+func (f *BandedLUFactor) Dim() int {
+	return f.n
+}
+
+// dst may be b itself
+func (f *BandedLUFactor) SolveTo(dst, b []float64) error {
+	if len(b) != f.n || len(dst) != f.n {
+		return errors.New("factor and vector dims don't match")
+	}
+
+	n := f.n
+	copy(dst, b)
+	for k := 0; k < n; k++ {
+		if p := f.piv[k]; p != k {
+			dst[k], dst[p] = dst[p], dst[k]
+		}
+		for r, m := range f.l[k] {
+			if k + r + 1 < n {
+				dst[k + r + 1] -= m * dst[k]
+			}
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		sum := dst[i]
+		for j := i + 1; j <= intMin(n - 1, i + f.w - 1); j++ {
+			sum -= f.u[i][j - i] * dst[j]
+		}
+		dst[i] = sum / f.u[i][0]
+	}
+
+	return nil
+}
+
+func (f *BandedLUFactor) Solve(b []float64) ([]float64, error) {
+	x := make([]float64, f.n)
+	if err := f.SolveTo(x, b); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (f *BandedLUFactor) SolveMany(b *Matrix) (*Matrix, error) {
+	return solveMany(f.n, f.n, b, f.SolveTo)
+}
+
+func (f *BandedLUFactor) Det() (float64, error) {
+	res := f.sign
+	for i := 0; i < f.n; i++ {
+		res *= f.u[i][0]
+	}
+	return res, nil
+}
+
+func FactorizeBandedCholesky(b *Banded) (*BandedCholeskyFactor, error) {
+	if !b.IsSymmetric() {
+		return nil, errors.New("matrix isn't symmetric")
+	}
+
+	n, k := b.n, intMax(b.kl, b.ku)
+	l := init2dSlice(n, k + 1)
+	for i := 0; i < n; i++ {
+		for j := intMax(0, i - k); j <= i; j++ {
+			s := b.At(i, j)
+			for p := intMax(0, i - k); p < j; p++ {
+				s -= l[i][p - i + k] * l[j][p - j + k]
+			}
+			if j < i {
+				l[i][j - i + k] = s / l[j][k]
+			} else if s <= 0 {
+				return nil, errors.New("matrix isn't positive definite")
+			} else {
+				l[i][k] = math.Sqrt(s)
+			}
+		}
+	}
+
+	return &BandedCholeskyFactor{l: l, n: n, k: k}, nil
+}
+
+func (f *BandedCholeskyFactor) Dim() int {
+	return f.n
+}
+
+func (f *BandedCholeskyFactor) L() *Banded {
+	res, _ := InitBanded(f.n, f.k, 0)
+	for i := range res.data {
+		copy(res.data[i], f.l[i])
+	}
+	return res
+}
+
+// dst may be b itself
+func (f *BandedCholeskyFactor) SolveTo(dst, b []float64) error {
+	if len(b) != f.n || len(dst) != f.n {
+		return errors.New("factor and vector dims don't match")
+	}
+
+	n, k := f.n, f.k
+	for i := 0; i < n; i++ {
+		sum := b[i]
+		for j := intMax(0, i - k); j < i; j++ {
+			sum -= f.l[i][j - i + k] * dst[j]
+		}
+		dst[i] = sum / f.l[i][k]
+	}
+	for i := n - 1; i >= 0; i-- {
+		sum := dst[i]
+		for j := i + 1; j <= intMin(n - 1, i + k); j++ {
+			sum -= f.l[j][i - j + k] * dst[j]
+		}
+		dst[i] = sum / f.l[i][k]
+	}
+
+	return nil
+}
+
+func (f *BandedCholeskyFactor) Solve(b []float64) ([]float64, error) {
+	x := make([]float64, f.n)
+	if err := f.SolveTo(x, b); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (f *BandedCholeskyFactor) SolveMany(b *Matrix) (*Matrix, error) {
+	return solveMany(f.n, f.n, b, f.SolveTo)
+}
+
+func (f *BandedCholeskyFactor) Det() (float64, error) {
+	res := float64(1)
+	for i := 0; i < f.n; i++ {
+		res *= f.l[i][f.k] * f.l[i][f.k]
+	}
+	return res, nil
+}
@@ -0,0 +1,249 @@
+package algnum
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func randBandedData(n, kl, ku int, dominant bool) [][]float64 {
+	data := init2dSlice(n, n)
+	for i := 0; i < n; i++ {
+		var sum float64
+		for j := intMax(0, i - kl); j <= intMin(n - 1, i + ku); j++ {
+			if j != i {
+				data[i][j] = float64(rand.Intn(19) - 9)
+				sum += math.Abs(data[i][j])
+			}
+		}
+		if dominant {
+			data[i][i] = sum + float64(rand.Intn(5) + 1)
+		} else {
+			data[i][i] = float64(rand.Intn(3) - 1)
+		}
+	}
+	return data
+}
+
+func TestTridiagonal(t *testing.T) {
+	n := 50
+	for _, dominant := range []bool{true, false} {
+		data := randBandedData(n, 1, 1, dominant)
+		sub, diag, super := make([]float64, n - 1), make([]float64, n), make([]float64, n - 1)
+		for i := 0; i < n; i++ {
+			diag[i] = data[i][i]
+			if i < n - 1 {
+				sub[i], super[i] = data[i + 1][i], data[i][i + 1]
+			}
+		}
+		tri, err := InitTridiagonal(sub, diag, super)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tri.IsDiagDominant() != (matOfRows(data, n, n)).IsDiagDominant() {
+			t.Fatal("tridiagonal and dense diagonal dominance differ")
+		}
+		if !MatsEq(tri.ToMat(), matOfRows(data, n, n), 1e-12) {
+			t.Fatal("tridiagonal storage is wrong")
+		}
+
+		f := randFree(n, 1, 10)
+		lapack, err := lapackSolve(data, n, f)
+		if err != nil {
+			continue
+		}
+		eps := 1e-9 * (1 + VecNorm(lapack, InfinityNorm))
+		x, err := tri.Solve(f)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(lapack, x, eps) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(x))
+		}
+		if x, _ = Gauss(tri, f); !VectsEq(lapack, x, eps) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(x))
+		}
+	}
+
+	if _, err := InitTridiagonal([]float64{1}, []float64{1, 2}, nil); err == nil {
+		t.Fatal("expected diagonals dims error")
+	} else {
+		t.Log("tridiagonal works correct")
+	}
+}
+
+func TestCyclicTridiagonal(t *testing.T) {
+	n := 40
+	for _, dominant := range []bool{true, false} {
+		data := randBandedData(n, 1, 1, dominant)
+		lower, upper := float64(rand.Intn(9) + 1), float64(rand.Intn(9) - 9)
+		if dominant {
+			data[0][0] += math.Abs(upper)
+			data[n - 1][n - 1] += math.Abs(lower)
+		}
+		sub, diag, super := make([]float64, n - 1), make([]float64, n), make([]float64, n - 1)
+		for i := 0; i < n; i++ {
+			diag[i] = data[i][i]
+			if i < n - 1 {
+				sub[i], super[i] = data[i + 1][i], data[i][i + 1]
+			}
+		}
+		tri, _ := InitTridiagonal(sub, diag, super)
+
+		full := copy2dSlice(data)
+		full[n - 1][0], full[0][n - 1] = lower, upper
+		f := randFree(n, 1, 10)
+		x, err := tri.SolveCyclic(f, lower, upper)
+		if !dominant {
+			if err == nil {
+				t.Fatal("expected diagonal dominance error")
+			}
+			continue
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		lapack, err := lapackSolve(full, n, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VectsEq(lapack, x, 1e-8) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(x))
+		}
+	}
+
+	// dominant without the corners, not with them
+	tri, _ := InitTridiagonal([]float64{1, 1}, []float64{3, 3, 3}, []float64{1, 1})
+	if _, err := tri.SolveCyclic([]float64{1, 1, 1}, 1, 3); err == nil {
+		t.Fatal("expected diagonal dominance error")
+	}
+	if _, err := tri.SolveCyclic([]float64{1, 1, 1}, 2, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	tri, _ = InitTridiagonal([]float64{1}, []float64{4, 4}, []float64{1})
+	if _, err := tri.SolveCyclic([]float64{1, 1}, 1, 1); err == nil {
+		t.Fatal("expected too small system error")
+	} else {
+		t.Log("cyclic tridiagonal works correct")
+	}
+}
+
+func TestBandedLU(t *testing.T) {
+	n := 60
+	for _, bw := range [][2]int{{1, 1}, {2, 2}, {3, 1}, {0, 2}, {2, 0}} {
+		for _, dominant := range []bool{true, false} {
+			kl, ku := bw[0], bw[1]
+			data := randBandedData(n, kl, ku, dominant)
+			b, err := MatToBanded(matOfRows(data, n, n), kl, ku)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.IsDiagDominant() != (matOfRows(data, n, n)).IsDiagDominant() {
+				t.Fatal("banded and dense diagonal dominance differ")
+			}
+
+			f := randFree(n, 1, 10)
+			lapack, err := lapackSolve(data, n, f)
+			if err != nil {
+				continue
+			}
+			lu, err := FactorizeBandedLU(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			x, _ := lu.Solve(f)
+			if !VectsEq(lapack, x, 1e-9 * (1 + VecNorm(lapack, InfinityNorm))) {
+				t.Fatalf("result is wrong for kl=%d ku=%d: expected\n %s,\ngot\n %s", kl, ku, VectToStr(lapack), VectToStr(x))
+			}
+			if x, _ = Gauss(b, f); !VectsEq(lapack, x, 1e-9 * (1 + VecNorm(lapack, InfinityNorm))) {
+				t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(x))
+			}
+
+			det, _ := lu.Det()
+			if expected := lapackDet(data, n); math.Abs(det - expected) > 1e-8 * math.Abs(expected) {
+				t.Fatalf("result is wrong: expected det %e, got %e", expected, det)
+			}
+
+			y := make([]float64, n)
+			b.MulVec(y, x)
+			if !VectsEq(f, y, 1e-9 * (1 + VecNorm(x, InfinityNorm)) * 10 * float64(kl + ku + 1)) {
+				t.Fatal("result is wrong: A * x != f")
+			}
+		}
+	}
+
+	if _, err := MatToBanded(matOfRows([][]float64{{1, 0, 1}, {0, 1, 0}, {0, 0, 1}}, 3, 3), 1, 1); err == nil {
+		t.Fatal("expected nonzeros outside the band error")
+	}
+	b, _ := InitBanded(3, 1, 1)
+	_ = b.Set(0, 0, 1)
+	_ = b.Set(1, 1, 1)
+	if err := b.Set(0, 2, 1); err == nil {
+		t.Fatal("expected outside the band error")
+	}
+	if _, err := FactorizeBandedLU(b); err == nil {
+		t.Fatal("expected singular matrix error")
+	} else {
+		t.Log("banded lu works correct")
+	}
+}
+
+func TestBandedCholesky(t *testing.T) {
+	n, k := 60, 3
+	data := randBandedData(n, k, k, true)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			data[i][j] = data[j][i]
+		}
+	}
+	for i := 0; i < n; i++ {
+		var sum float64
+		for j := 0; j < n; j++ {
+			if j != i {
+				sum += math.Abs(data[i][j])
+			}
+		}
+		data[i][i] = sum + 1
+	}
+	b, _ := MatToBanded(matOfRows(data, n, n), k, k)
+
+	ch, err := FactorizeBandedCholesky(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := randFree(n, 1, 10)
+	lapack, _ := lapackSolve(data, n, f)
+	x, _ := ch.Solve(f)
+	if !VectsEq(lapack, x, 1e-8) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(x))
+	}
+
+	l := ch.L().ToMat()
+	llt := init2dSlice(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			for p := 0; p < n; p++ {
+				llt[i][j] += l.data[i * l.stride + p] * l.data[j * l.stride + p]
+			}
+		}
+	}
+	if !MatsEq(matOfRows(llt, n, n), matOfRows(data, n, n), 1e-9) {
+		t.Fatal("result is wrong: L * L^T != A")
+	}
+
+	det, _ := ch.Det()
+	if expected := lapackDet(data, n); math.Abs(det - expected) > 1e-8 * math.Abs(expected) {
+		t.Fatalf("result is wrong: expected det %e, got %e", expected, det)
+	}
+
+	data[0][0] = -1
+	b, _ = MatToBanded(matOfRows(data, n, n), k, k)
+	if _, err := FactorizeBandedCholesky(b); err == nil {
+		t.Fatal("expected not positive definite error")
+	}
+	_ = b.Set(0, 1, data[0][1] + 1)
+	if _, err := FactorizeBandedCholesky(b); err == nil {
+		t.Fatal("expected not symmetric error")
+	} else {
+		t.Log("banded cholesky works correct")
+	}
+}
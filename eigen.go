@@ -0,0 +1,455 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+)
+
+const eigenMaxIter = 30
+
+// all eigenvalues of a symmetric matrix in ascending order and, if vectors
+// is set, the orthonormal eigenvectors as the columns of the returned matrix
+func EigenSym(m *Matrix, vectors bool) ([]float64, *Matrix, error) {
+	if m.rows == 0 {
+		return nil, nil, errors.New("matrix is empty")
+	}
+	if !m.IsSymmetric() {
+		return nil, nil, errors.New("matrix isn't symmetric")
+	}
+	n := m.rows
+
+	v := copy2dSlice(m.rowSlices())
+	d, e := make([]float64, n), make([]float64, n)
+	tridiagonalize(v, d, e, vectors)
+	if err := tridiagonalQL(v, d, e, vectors); err != nil {
+		return nil, nil, err
+	}
+
+	if !vectors {
+		return d, nil, nil
+	}
+	return d, matOfRows(v, n, n), nil
+}
+
+// eigenpairs with indexes lo..hi (inclusive) of the ascending spectrum.
+// Only the selected pairs are computed: the eigenvalues by bisection with
+// Sturm counts on the tridiagonal form, the eigenvectors by inverse
+// iteration on it
+func EigenSymIndexRange(m *Matrix, lo, hi int, vectors bool) ([]float64, *Matrix, error) {
+	if lo < 0 || hi >= m.rows || lo > hi {
+		return nil, nil, errors.New("wrong indexes")
+	}
+
+	t, err := tridiagonalOf(m, vectors)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t.selectEigen(lo, hi + 1, vectors)
+}
+
+// eigenpairs with values in (vl, vu], computed like EigenSymIndexRange
+func EigenSymValueRange(m *Matrix, vl, vu float64, vectors bool) ([]float64, *Matrix, error) {
+	if vl >= vu {
+		return nil, nil, errors.New("wrong interval")
+	}
+
+	t, err := tridiagonalOf(m, vectors)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t.selectEigen(t.count(vl), t.count(vu), vectors)
+}
+
+// A = Q T Qᵀ with T tridiagonal: diagonal d and off diagonal e[1:], e[i]
+// joining rows i - 1 and i; q is kept only when vectors are wanted.
+// [lo, hi] is the Gershgorin interval of T and pivmin the size below
+// which pivots count as zero
+type symTridiag struct {
+	d, e   []float64
+	q      [][]float64
+	lo, hi float64
+	norm   float64
+	pivmin float64
+}
+
+func tridiagonalOf(m *Matrix, vectors bool) (*symTridiag, error) {
+	if m.rows == 0 {
+		return nil, errors.New("matrix is empty")
+	}
+	if !m.IsSymmetric() {
+		return nil, errors.New("matrix isn't symmetric")
+	}
+	n := m.rows
+
+	v := copy2dSlice(m.rowSlices())
+	d, e := make([]float64, n), make([]float64, n)
+	tridiagonalize(v, d, e, vectors)
+
+	t := &symTridiag{d: d, e: e, lo: d[0], hi: d[0]}
+	if vectors {
+		t.q = v
+	}
+	for i := 0; i < n; i++ {
+		var r float64
+		if i > 0 {
+			r += math.Abs(e[i])
+		}
+		if i + 1 < n {
+			r += math.Abs(e[i + 1])
+		}
+		t.lo, t.hi = math.Min(t.lo, d[i] - r), math.Max(t.hi, d[i] + r)
+	}
+	t.norm = math.Max(math.Abs(t.lo), math.Abs(t.hi))
+	if t.norm == 0 {
+		t.norm = 1
+	}
+	t.pivmin = machEps * t.norm
+	t.lo -= 2 * t.pivmin
+	t.hi += 2 * t.pivmin
+	return t, nil
+}
+
+// number of eigenvalues of T not greater than x: the negative pivots of
+// the LDLᵀ factorization of T - xI
+func (t *symTridiag) count(x float64) int {
+	res := 0
+	var q float64
+	for i := range t.d {
+		if i == 0 {
+			q = t.d[0] - x
+		} else {
+			q = t.d[i] - x - t.e[i] * t.e[i] / q
+		}
+		if math.Abs(q) < t.pivmin {
+			q = -t.pivmin
+		}
+		if q < 0 {
+			res++
+		}
+	}
+	return res
+}
+
+// eigenvalue k of the ascending spectrum by bisection, count(lo) <= k < count(hi)
+func (t *symTridiag) eigenvalue(k int) float64 {
+	lo, hi := t.lo, t.hi
+	for hi - lo > 2 * machEps * math.Max(math.Abs(lo), math.Abs(hi)) + t.pivmin {
+		mid := lo + (hi - lo) / 2
+		if t.count(mid) > k {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return lo + (hi - lo) / 2
+}
+
+// solves (T - λI) z = b in place by Gaussian elimination with partial
+// pivoting; U has two superdiagonals, tiny pivots are replaced by pivmin
+func (t *symTridiag) shiftedSolve(lambda float64, b []float64, u0, u1, u2, mult []float64, swap []bool) {
+	n := len(t.d)
+	dg, up := t.d[0] - lambda, 0.
+	if n > 1 {
+		up = t.e[1]
+	}
+	for i := 0; i < n - 1; i++ {
+		sub, nd, nu := t.e[i + 1], t.d[i + 1] - lambda, 0.
+		if i + 2 < n {
+			nu = t.e[i + 2]
+		}
+		if math.Abs(dg) >= math.Abs(sub) {
+			if math.Abs(dg) < t.pivmin {
+				dg = t.pivmin
+			}
+			mult[i], swap[i] = sub / dg, false
+			u0[i], u1[i], u2[i] = dg, up, 0
+			dg, up = nd - mult[i] * up, nu
+		} else {
+			mult[i], swap[i] = dg / sub, true
+			u0[i], u1[i], u2[i] = sub, nd, nu
+			dg, up = up - mult[i] * nd, -mult[i] * nu
+		}
+	}
+	if math.Abs(dg) < t.pivmin {
+		dg = t.pivmin
+	}
+	u0[n - 1] = dg
+
+	for i := 0; i < n - 1; i++ {
+		if swap[i] {
+			b[i], b[i + 1] = b[i + 1], b[i] - mult[i] * b[i + 1]
+		} else {
+			b[i + 1] -= mult[i] * b[i]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		sum := b[i]
+		if i + 1 < n {
+			sum -= u1[i] * b[i + 1]
+		}
+		if i + 2 < n {
+			sum -= u2[i] * b[i + 2]
+		}
+		b[i] = sum / u0[i]
+	}
+}
+
+// eigenvalues from..to - 1 and, if vectors is set, their eigenvectors as
+// columns; vectors of eigenvalues closer than 1e-3 * ||T|| are
+// orthogonalized against each other during the inverse iteration
+func (t *symTridiag) selectEigen(from, to int, vectors bool) ([]float64, *Matrix, error) {
+	vals := make([]float64, to - from)
+	for k := range vals {
+		vals[k] = t.eigenvalue(from + k)
+	}
+	if !vectors {
+		return vals, nil, nil
+	}
+
+	n := len(t.d)
+	u0, u1, u2, mult := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	swap := make([]bool, n)
+	zs := init2dSlice(len(vals), n)
+	clusterStart := 0
+	var lambda float64
+	for k, val := range vals {
+		// equal eigenvalues are pulled apart, so the solves differ
+		sep := 10 * machEps * t.norm
+		if k > 0 && val - vals[k - 1] < 1e-3 * t.norm {
+			lambda = math.Max(val, lambda + sep)
+		} else {
+			clusterStart, lambda = k, val
+		}
+
+		z := zs[k]
+		for i := range z {
+			z[i] = 1 + float64(i % 7) / 10
+		}
+		for iter := 0; iter < 3; iter++ {
+			normalize(z)
+			t.shiftedSolve(lambda, z, u0, u1, u2, mult, swap)
+			for j := clusterStart; j < k; j++ {
+				c := dot(z, zs[j])
+				for i := range z {
+					z[i] -= c * zs[j][i]
+				}
+			}
+		}
+		normalize(z)
+	}
+
+	res := newMat(n, len(vals))
+	for i := 0; i < n; i++ {
+		ri := res.row(i)
+		for j, z := range zs {
+			ri[j] = dot(t.q[i], z)
+		}
+	}
+	return vals, res, nil
+}
+
+// Householder reduction of the symmetric matrix in v to tridiagonal form:
+// d gets the diagonal, e[1:] the subdiagonal and v the accumulated
+// orthogonal transformation if vectors is set
+func tridiagonalize(v [][]float64, d, e []float64, vectors bool) {
+	n := len(v)
+	for j := 0; j < n; j++ {
+		d[j] = v[n - 1][j]
+	}
+
+	for i := n - 1; i > 0; i-- {
+		var scale, h float64
+		for k := 0; k < i; k++ {
+			scale += math.Abs(d[k])
+		}
+		if scale == 0 {
+			e[i] = d[i - 1]
+			for j := 0; j < i; j++ {
+				d[j] = v[i - 1][j]
+				v[i][j] = 0
+				v[j][i] = 0
+			}
+		} else {
+			for k := 0; k < i; k++ {
+				d[k] /= scale
+				h += d[k] * d[k]
+			}
+			f := d[i - 1]
+			g := math.Sqrt(h)
+			if f > 0 {
+				g = -g
+			}
+			e[i] = scale * g
+			h -= f * g
+			d[i - 1] = f - g
+			for j := 0; j < i; j++ {
+				e[j] = 0
+			}
+
+			for j := 0; j < i; j++ {
+				f = d[j]
+				v[j][i] = f
+				g = e[j] + v[j][j] * f
+				for k := j + 1; k <= i - 1; k++ {
+					g += v[k][j] * d[k]
+					e[k] += v[k][j] * f
+				}
+				e[j] = g
+			}
+			f = 0
+			for j := 0; j < i; j++ {
+				e[j] /= h
+				f += e[j] * d[j]
+			}
+			hh := f / (h + h)
+			for j := 0; j < i; j++ {
+				e[j] -= hh * d[j]
+			}
+			for j := 0; j < i; j++ {
+				f = d[j]
+				g = e[j]
+				for k := j; k <= i - 1; k++ {
+					v[k][j] -= f * e[k] + g * d[k]
+				}
+				d[j] = v[i - 1][j]
+				v[i][j] = 0
+			}
+		}
+		d[i] = h
+	}
+
+	if !vectors {
+		for i := 0; i < n; i++ {
+			d[i] = v[i][i]
+		}
+		e[0] = 0
+		return
+	}
+
+	for i := 0; i < n - 1; i++ {
+		v[n - 1][i] = v[i][i]
+		v[i][i] = 1
+		h := d[i + 1]
+		if h != 0 {
+			for k := 0; k <= i; k++ {
+				d[k] = v[k][i + 1] / h
+			}
+			for j := 0; j <= i; j++ {
+				var g float64
+				for k := 0; k <= i; k++ {
+					g += v[k][i + 1] * v[k][j]
+				}
+				for k := 0; k <= i; k++ {
+					v[k][j] -= g * d[k]
+				}
+			}
+		}
+		for k := 0; k <= i; k++ {
+			v[k][i + 1] = 0
+		}
+	}
+	for j := 0; j < n; j++ {
+		d[j] = v[n - 1][j]
+		v[n - 1][j] = 0
+	}
+	v[n - 1][n - 1] = 1
+	e[0] = 0
+}
+
+// implicit QL iterations on the tridiagonal matrix (d, e), the rotations
+// are accumulated into v if vectors is set; eigenvalues end up sorted
+func tridiagonalQL(v [][]float64, d, e []float64, vectors bool) error {
+	n := len(d)
+	for i := 1; i < n; i++ {
+		e[i - 1] = e[i]
+	}
+	e[n - 1] = 0
+
+	var f, tst1 float64
+	for l := 0; l < n; l++ {
+		tst1 = math.Max(tst1, math.Abs(d[l]) + math.Abs(e[l]))
+		m := l
+		for m < n - 1 && math.Abs(e[m]) > machEps * tst1 {
+			m++
+		}
+
+		if m > l {
+			for iter := 0; ; iter++ {
+				if iter == eigenMaxIter * n {
+					return errors.New("eigenvalues didn't converge")
+				}
+
+				g := d[l]
+				p := (d[l + 1] - g) / (2 * e[l])
+				r := math.Hypot(p, 1)
+				if p < 0 {
+					r = -r
+				}
+				d[l] = e[l] / (p + r)
+				d[l + 1] = e[l] * (p + r)
+				dl1 := d[l + 1]
+				h := g - d[l]
+				for i := l + 2; i < n; i++ {
+					d[i] -= h
+				}
+				f += h
+
+				p = d[m]
+				c, c2, c3 := float64(1), float64(1), float64(1)
+				el1 := e[l + 1]
+				var s, s2 float64
+				for i := m - 1; i >= l; i-- {
+					c3 = c2
+					c2 = c
+					s2 = s
+					g = c * e[i]
+					h = c * p
+					r = math.Hypot(p, e[i])
+					e[i + 1] = s * r
+					s = e[i] / r
+					c = p / r
+					p = c * d[i] - s * g
+					d[i + 1] = h + s * (c * g + s * d[i])
+					if vectors {
+						for k := 0; k < n; k++ {
+							h = v[k][i + 1]
+							v[k][i + 1] = s * v[k][i] + c * h
+							v[k][i] = c * v[k][i] - s * h
+						}
+					}
+				}
+				p = -s * s2 * c3 * el1 * e[l] / dl1
+				e[l] = s * p
+				d[l] = c * p
+
+				if math.Abs(e[l]) <= machEps * tst1 {
+					break
+				}
+			}
+		}
+		d[l] += f
+		e[l] = 0
+	}
+
+	for i := 0; i < n - 1; i++ {
+		k := i
+		p := d[i]
+		for j := i + 1; j < n; j++ {
+			if d[j] < p {
+				k = j
+				p = d[j]
+			}
+		}
+		if k != i {
+			d[k] = d[i]
+			d[i] = p
+			if vectors {
+				swapCols(v, i, k)
+			}
+		}
+	}
+
+	return nil
+}
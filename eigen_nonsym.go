@@ -0,0 +1,604 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+)
+
+type EigenFactor struct {
+	values      []complex128
+	right, left [][]complex128
+}
+
+// eigenvalues of a general square matrix via Hessenberg reduction and
+// Francis double-shift QR; right and left request the eigenvectors with
+// A * x = λ * x and y^H * A = λ * y^H, both normalized to unit length
+func Eigen(m *Matrix, right, left bool) (*EigenFactor, error) {
+	if !m.IsSquare() {
+		return nil, errors.New("matrix isn't square")
+	}
+	if m.rows == 0 {
+		return nil, errors.New("matrix is empty")
+	}
+
+	values, rightVecs, err := eigenHQR(m.rowSlices(), right)
+	if err != nil {
+		return nil, err
+	}
+	res := &EigenFactor{values: values, right: rightVecs}
+
+	if left {
+		// y^H * A = λ * y^H means A^T * conj(y) = λ * conj(y)
+		tValues, tVecs, err := eigenHQR(transpose2dSlice(m.rowSlices(), m.rows, m.cols), true)
+		if err != nil {
+			return nil, err
+		}
+		res.left = make([][]complex128, len(values))
+		used := make([]bool, len(tValues))
+		for i, l := range values {
+			best := -1
+			for j, tl := range tValues {
+				if !used[j] && (best < 0 || cmplx.Abs(tl - l) < cmplx.Abs(tValues[best] - l)) {
+					best = j
+				}
+			}
+			used[best] = true
+			y := tVecs[best]
+			for k := range y {
+				y[k] = cmplx.Conj(y[k])
+			}
+			res.left[i] = y
+		}
+	}
+
+	return res, nil
+}
+
+func (f *EigenFactor) Values() []complex128 {
+	res := make([]complex128, len(f.values))
+	copy(res, f.values)
+	return res
+}
+
+// i-th vector belongs to the i-th eigenvalue, nil unless requested
+func (f *EigenFactor) RightVectors() [][]complex128 {
+	return copyComplexVecs(f.right)
+}
+
+func (f *EigenFactor) LeftVectors() [][]complex128 {
+	return copyComplexVecs(f.left)
+}
+
+func (f *EigenFactor) SpectralRadius() float64 {
+	var res float64
+	for _, l := range f.values {
+		res = math.Max(res, cmplx.Abs(l))
+	}
+	return res
+}
+
+func copyComplexVecs(vecs [][]complex128) [][]complex128 {
+	if vecs == nil {
+		return nil
+	}
+	res := make([][]complex128, len(vecs))
+	for i := range vecs {
+		res[i] = make([]complex128, len(vecs[i]))
+		copy(res[i], vecs[i])
+	}
+	return res
+}
+
+func eigenHQR(data [][]float64, vectors bool) ([]complex128, [][]complex128, error) {
+	n := len(data)
+	h := copy2dSlice(data)
+	v := init2dSlice(n, n)
+	for i := 0; i < n; i++ {
+		v[i][i] = 1
+	}
+	d, e := make([]float64, n), make([]float64, n)
+
+	hessenberg(h, v, vectors)
+	if err := hessenbergQR(h, v, d, e, vectors); err != nil {
+		return nil, nil, err
+	}
+
+	values := make([]complex128, n)
+	for i := range values {
+		values[i] = complex(d[i], e[i])
+	}
+	if !vectors {
+		return values, nil, nil
+	}
+
+	vecs := make([][]complex128, n)
+	for j := 0; j < n; j++ {
+		x := make([]complex128, n)
+		switch {
+		case e[j] == 0:
+			for i := 0; i < n; i++ {
+				x[i] = complex(v[i][j], 0)
+			}
+		case e[j] > 0:
+			for i := 0; i < n; i++ {
+				x[i] = complex(v[i][j], v[i][j + 1])
+			}
+		default:
+			for i := 0; i < n; i++ {
+				x[i] = complex(v[i][j - 1], -v[i][j])
+			}
+		}
+
+		var norm float64
+		for _, xi := range x {
+			norm = math.Hypot(norm, cmplx.Abs(xi))
+		}
+		if norm != 0 {
+			for i := range x {
+				x[i] /= complex(norm, 0)
+			}
+		}
+		vecs[j] = x
+	}
+
+	return values, vecs, nil
+}
+
+// orthogonal reduction to upper Hessenberg form by Householder reflections,
+// accumulated into v if vectors is set
+func hessenberg(h, v [][]float64, vectors bool) {
+	n := len(h)
+	high := n - 1
+	ort := make([]float64, n)
+
+	for m := 1; m <= high - 1; m++ {
+		var scale float64
+		for i := m; i <= high; i++ {
+			scale += math.Abs(h[i][m - 1])
+		}
+		if scale == 0 {
+			continue
+		}
+
+		var hh float64
+		for i := high; i >= m; i-- {
+			ort[i] = h[i][m - 1] / scale
+			hh += ort[i] * ort[i]
+		}
+		g := math.Sqrt(hh)
+		if ort[m] > 0 {
+			g = -g
+		}
+		hh -= ort[m] * g
+		ort[m] -= g
+
+		for j := m; j < n; j++ {
+			var f float64
+			for i := high; i >= m; i-- {
+				f += ort[i] * h[i][j]
+			}
+			f /= hh
+			for i := m; i <= high; i++ {
+				h[i][j] -= f * ort[i]
+			}
+		}
+		for i := 0; i <= high; i++ {
+			var f float64
+			for j := high; j >= m; j-- {
+				f += ort[j] * h[i][j]
+			}
+			f /= hh
+			for j := m; j <= high; j++ {
+				h[i][j] -= f * ort[j]
+			}
+		}
+		ort[m] *= scale
+		h[m][m - 1] = scale * g
+	}
+
+	if !vectors {
+		return
+	}
+
+	for m := high - 1; m >= 1; m-- {
+		if h[m][m - 1] == 0 {
+			continue
+		}
+		for i := m + 1; i <= high; i++ {
+			ort[i] = h[i][m - 1]
+		}
+		for j := m; j <= high; j++ {
+			var g float64
+			for i := m; i <= high; i++ {
+				g += ort[i] * v[i][j]
+			}
+			// double division avoids possible underflow
+			g = (g / ort[m]) / h[m][m - 1]
+			for i := m; i <= high; i++ {
+				v[i][j] += g * ort[i]
+			}
+		}
+	}
+}
+
+// Francis double-shift QR on the Hessenberg matrix h down to real Schur
+// form; d and e get the real and imaginary parts of the eigenvalues,
+// with vectors set v ends up holding the eigenvectors (a complex pair
+// d[j] ± i*e[j] with e[j] > 0 is stored as columns j and j+1)
+func hessenbergQR(h, v [][]float64, d, e []float64, vectors bool) error {
+	nn := len(h)
+	n := nn - 1
+	low, high := 0, nn - 1
+	var exshift, p, q, r, s, z, t, w, x, y float64
+
+	var norm float64
+	for i := 0; i < nn; i++ {
+		for j := intMax(i - 1, 0); j < nn; j++ {
+			norm += math.Abs(h[i][j])
+		}
+	}
+
+	iter, totalIter := 0, 0
+	for n >= low {
+		// look for a single small subdiagonal element
+		l := n
+		for l > low {
+			s = math.Abs(h[l - 1][l - 1]) + math.Abs(h[l][l])
+			if s == 0 {
+				s = norm
+			}
+			if math.Abs(h[l][l - 1]) < machEps * s {
+				break
+			}
+			l--
+		}
+
+		if l == n {
+			// one root found
+			h[n][n] += exshift
+			d[n] = h[n][n]
+			e[n] = 0
+			n--
+			iter = 0
+		} else if l == n - 1 {
+			// two roots found
+			w = h[n][n - 1] * h[n - 1][n]
+			p = (h[n - 1][n - 1] - h[n][n]) / 2
+			q = p * p + w
+			z = math.Sqrt(math.Abs(q))
+			h[n][n] += exshift
+			h[n - 1][n - 1] += exshift
+			x = h[n][n]
+
+			if q >= 0 {
+				// real pair
+				if p >= 0 {
+					z = p + z
+				} else {
+					z = p - z
+				}
+				d[n - 1] = x + z
+				d[n] = d[n - 1]
+				if z != 0 {
+					d[n] = x - w / z
+				}
+				e[n - 1] = 0
+				e[n] = 0
+				x = h[n][n - 1]
+				s = math.Abs(x) + math.Abs(z)
+				p = x / s
+				q = z / s
+				r = math.Sqrt(p * p + q * q)
+				p /= r
+				q /= r
+
+				for j := n - 1; j < nn; j++ {
+					z = h[n - 1][j]
+					h[n - 1][j] = q * z + p * h[n][j]
+					h[n][j] = q * h[n][j] - p * z
+				}
+				for i := 0; i <= n; i++ {
+					z = h[i][n - 1]
+					h[i][n - 1] = q * z + p * h[i][n]
+					h[i][n] = q * h[i][n] - p * z
+				}
+				if vectors {
+					for i := low; i <= high; i++ {
+						z = v[i][n - 1]
+						v[i][n - 1] = q * z + p * v[i][n]
+						v[i][n] = q * v[i][n] - p * z
+					}
+				}
+			} else {
+				// complex pair
+				d[n - 1] = x + p
+				d[n] = x + p
+				e[n - 1] = z
+				e[n] = -z
+			}
+			n -= 2
+			iter = 0
+		} else {
+			if totalIter == eigenMaxIter * nn {
+				return errors.New("eigenvalues didn't converge")
+			}
+
+			x = h[n][n]
+			y = 0
+			w = 0
+			if l < n {
+				y = h[n - 1][n - 1]
+				w = h[n][n - 1] * h[n - 1][n]
+			}
+
+			// Wilkinson's original ad hoc shift
+			if iter == 10 {
+				exshift += x
+				for i := low; i <= n; i++ {
+					h[i][i] -= x
+				}
+				s = math.Abs(h[n][n - 1]) + math.Abs(h[n - 1][n - 2])
+				x = 0.75 * s
+				y = x
+				w = -0.4375 * s * s
+			}
+
+			// MATLAB's ad hoc shift
+			if iter == 30 {
+				s = (y - x) / 2
+				s = s * s + w
+				if s > 0 {
+					s = math.Sqrt(s)
+					if y < x {
+						s = -s
+					}
+					s = x - w / ((y - x) / 2 + s)
+					for i := low; i <= n; i++ {
+						h[i][i] -= s
+					}
+					exshift += s
+					x = 0.964
+					y = x
+					w = x
+				}
+			}
+
+			iter++
+			totalIter++
+
+			// look for two consecutive small subdiagonal elements
+			m := n - 2
+			for m >= l {
+				z = h[m][m]
+				r = x - z
+				s = y - z
+				p = (r * s - w) / h[m + 1][m] + h[m][m + 1]
+				q = h[m + 1][m + 1] - z - r - s
+				r = h[m + 2][m + 1]
+				s = math.Abs(p) + math.Abs(q) + math.Abs(r)
+				p /= s
+				q /= s
+				r /= s
+				if m == l {
+					break
+				}
+				if math.Abs(h[m][m - 1]) * (math.Abs(q) + math.Abs(r)) <
+					machEps * (math.Abs(p) * (math.Abs(h[m - 1][m - 1]) + math.Abs(z) + math.Abs(h[m + 1][m + 1]))) {
+					break
+				}
+				m--
+			}
+
+			for i := m + 2; i <= n; i++ {
+				h[i][i - 2] = 0
+				if i > m + 2 {
+					h[i][i - 3] = 0
+				}
+			}
+
+			// double QR step on rows l..n and columns m..n
+			for k := m; k <= n - 1; k++ {
+				notLast := k != n - 1
+				if k != m {
+					p = h[k][k - 1]
+					q = h[k + 1][k - 1]
+					r = 0
+					if notLast {
+						r = h[k + 2][k - 1]
+					}
+					x = math.Abs(p) + math.Abs(q) + math.Abs(r)
+					if x == 0 {
+						continue
+					}
+					p /= x
+					q /= x
+					r /= x
+				}
+
+				s = math.Sqrt(p * p + q * q + r * r)
+				if p < 0 {
+					s = -s
+				}
+				if s == 0 {
+					continue
+				}
+
+				if k != m {
+					h[k][k - 1] = -s * x
+				} else if l != m {
+					h[k][k - 1] = -h[k][k - 1]
+				}
+				p += s
+				x = p / s
+				y = q / s
+				z = r / s
+				q /= p
+				r /= p
+
+				for j := k; j < nn; j++ {
+					p = h[k][j] + q * h[k + 1][j]
+					if notLast {
+						p += r * h[k + 2][j]
+						h[k + 2][j] -= p * z
+					}
+					h[k][j] -= p * x
+					h[k + 1][j] -= p * y
+				}
+				for i := 0; i <= intMin(n, k + 3); i++ {
+					p = x * h[i][k] + y * h[i][k + 1]
+					if notLast {
+						p += z * h[i][k + 2]
+						h[i][k + 2] -= p * r
+					}
+					h[i][k] -= p
+					h[i][k + 1] -= p * q
+				}
+				if vectors {
+					for i := low; i <= high; i++ {
+						p = x * v[i][k] + y * v[i][k + 1]
+						if notLast {
+							p += z * v[i][k + 2]
+							v[i][k + 2] -= p * r
+						}
+						v[i][k] -= p
+						v[i][k + 1] -= p * q
+					}
+				}
+			}
+		}
+	}
+
+	if !vectors || norm == 0 {
+		return nil
+	}
+
+	// back substitution for the eigenvectors of the quasi-triangular form
+	for n = nn - 1; n >= 0; n-- {
+		p = d[n]
+		q = e[n]
+
+		if q == 0 {
+			// real vector
+			l := n
+			h[n][n] = 1
+			for i := n - 1; i >= 0; i-- {
+				w = h[i][i] - p
+				r = 0
+				for j := l; j <= n; j++ {
+					r += h[i][j] * h[j][n]
+				}
+				if e[i] < 0 {
+					z = w
+					s = r
+					continue
+				}
+				l = i
+				if e[i] == 0 {
+					if w != 0 {
+						h[i][n] = -r / w
+					} else {
+						h[i][n] = -r / (machEps * norm)
+					}
+				} else {
+					x = h[i][i + 1]
+					y = h[i + 1][i]
+					q = (d[i] - p) * (d[i] - p) + e[i] * e[i]
+					t = (x * s - z * r) / q
+					h[i][n] = t
+					if math.Abs(x) > math.Abs(z) {
+						h[i + 1][n] = (-r - w * t) / x
+					} else {
+						h[i + 1][n] = (-s - y * t) / z
+					}
+				}
+
+				// overflow control
+				t = math.Abs(h[i][n])
+				if (machEps * t) * t > 1 {
+					for j := i; j <= n; j++ {
+						h[j][n] /= t
+					}
+				}
+			}
+		} else if q < 0 {
+			// complex vector, the last component is imaginary
+			// so the matrix is triangular
+			l := n - 1
+			if math.Abs(h[n][n - 1]) > math.Abs(h[n - 1][n]) {
+				h[n - 1][n - 1] = q / h[n][n - 1]
+				h[n - 1][n] = -(h[n][n] - p) / h[n][n - 1]
+			} else {
+				c := complex(0, -h[n - 1][n]) / complex(h[n - 1][n - 1] - p, q)
+				h[n - 1][n - 1] = real(c)
+				h[n - 1][n] = imag(c)
+			}
+			h[n][n - 1] = 0
+			h[n][n] = 1
+
+			var ra, sa float64
+			for i := n - 2; i >= 0; i-- {
+				ra, sa = 0, 0
+				for j := l; j <= n; j++ {
+					ra += h[i][j] * h[j][n - 1]
+					sa += h[i][j] * h[j][n]
+				}
+				w = h[i][i] - p
+
+				if e[i] < 0 {
+					z = w
+					r = ra
+					s = sa
+					continue
+				}
+				l = i
+				if e[i] == 0 {
+					c := complex(-ra, -sa) / complex(w, q)
+					h[i][n - 1] = real(c)
+					h[i][n] = imag(c)
+				} else {
+					x = h[i][i + 1]
+					y = h[i + 1][i]
+					vr := (d[i] - p) * (d[i] - p) + e[i] * e[i] - q * q
+					vi := (d[i] - p) * 2 * q
+					if vr == 0 && vi == 0 {
+						vr = machEps * norm * (math.Abs(w) + math.Abs(q) + math.Abs(x) + math.Abs(y) + math.Abs(z))
+					}
+					c := complex(x * r - z * ra + q * sa, x * s - z * sa - q * ra) / complex(vr, vi)
+					h[i][n - 1] = real(c)
+					h[i][n] = imag(c)
+					if math.Abs(x) > math.Abs(z) + math.Abs(q) {
+						h[i + 1][n - 1] = (-ra - w * h[i][n - 1] + q * h[i][n]) / x
+						h[i + 1][n] = (-sa - w * h[i][n] - q * h[i][n - 1]) / x
+					} else {
+						c = complex(-r - y * h[i][n - 1], -s - y * h[i][n]) / complex(z, q)
+						h[i + 1][n - 1] = real(c)
+						h[i + 1][n] = imag(c)
+					}
+				}
+
+				// overflow control
+				t = math.Max(math.Abs(h[i][n - 1]), math.Abs(h[i][n]))
+				if (machEps * t) * t > 1 {
+					for j := i; j <= n; j++ {
+						h[j][n - 1] /= t
+						h[j][n] /= t
+					}
+				}
+			}
+		}
+	}
+
+	// back transformation to the eigenvectors of the original matrix
+	for j := nn - 1; j >= low; j-- {
+		for i := low; i <= high; i++ {
+			z = 0
+			for k := low; k <= intMin(j, high); k++ {
+				z += v[i][k] * h[k][j]
+			}
+			v[i][j] = z
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package algnum
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math/cmplx"
+	"sort"
+	"testing"
+)
+
+func lapackEigen(m [][]float64, n int) []complex128 {
+	var mVec []float64
+	for i := 0; i < n; i++ {
+		mVec = append(mVec, m[i]...)
+	}
+	gonumA := mat.NewDense(n, n, mVec)
+
+	var eig mat.Eigen
+	eig.Factorize(gonumA, mat.EigenNone)
+
+	return eig.Values(nil)
+}
+
+func sortComplex(a []complex128) {
+	sort.Slice(a, func(i, j int) bool {
+		if real(a[i]) != real(a[j]) {
+			return real(a[i]) < real(a[j])
+		}
+		return imag(a[i]) < imag(a[j])
+	})
+}
+
+func complexVecsEq(a, b []complex128, eps float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if cmplx.Abs(a[i] - b[i]) >= eps {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEigen(t *testing.T) {
+	// rotation by 90 degrees has eigenvalues ±i
+	rotData := [][]float64{
+		{0, -1},
+		{1, 0},
+	}
+	rot, _ := InitMat(rotData)
+
+	f, err := Eigen(rot, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vals := f.Values()
+	sortComplex(vals)
+	if !complexVecsEq(vals, []complex128{-1i, 1i}, 1e-12) {
+		t.Fatalf("result is wrong: expected [-i, i], got %v", vals)
+	}
+
+	for _, n := range []int{1, 3, 8, 40} {
+		dataN := randData(n, n, -10, 10)
+		matN, _ := InitMat(dataN)
+
+		fN, err := Eigen(matN, true, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		valsN := fN.Values()
+		lapack := lapackEigen(dataN, n)
+		sortComplex(valsN)
+		sortComplex(lapack)
+		if !complexVecsEq(valsN, lapack, 1e-8) {
+			t.Fatalf("result is wrong: expected\n %v,\ngot\n %v", lapack, valsN)
+		}
+
+		values, right, left := fN.Values(), fN.RightVectors(), fN.LeftVectors()
+		for k, l := range values {
+			ax, yA := make([]complex128, n), make([]complex128, n)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					ax[i] += complex(dataN[i][j], 0) * right[k][j]
+					yA[j] += cmplx.Conj(left[k][i]) * complex(dataN[i][j], 0)
+				}
+			}
+			lx, ly := make([]complex128, n), make([]complex128, n)
+			for i := 0; i < n; i++ {
+				lx[i] = l * right[k][i]
+				ly[i] = l * cmplx.Conj(left[k][i])
+			}
+			if !complexVecsEq(ax, lx, 1e-8) {
+				t.Fatalf("A * x%d != λ%d * x%d", k, k, k)
+			}
+			if !complexVecsEq(yA, ly, 1e-8) {
+				t.Fatalf("y%d^H * A != λ%d * y%d^H", k, k, k)
+			}
+		}
+	}
+	t.Log("nonsymmetric eigensolver works correct")
+}
+
+func TestEigen_IterationMatrix(t *testing.T) {
+	data := [][]float64{
+		{3, 1, 1},
+		{1, 3, 2},
+		{1, 2, 4},
+	}
+	a, _ := InitMat(data)
+
+	// p = E - tA of the fixed-point iteration converges iff its spectral radius < 1
+	minL, maxL, err := MinMaxEigenvalues(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, _ := IdentityMat(3)
+	p, _ := MatsSub(e, MatConstMul(a, 2 / (minL + maxL)))
+
+	f, err := Eigen(p, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := (maxL - minL) / (maxL + minL)
+	if rho := f.SpectralRadius(); rho >= 1 || rho - expected > 1e-12 || expected - rho > 1e-12 {
+		t.Fatalf("spectral radius is wrong: expected %0.15f, got %0.15f", expected, rho)
+	} else {
+		t.Log("iteration matrix spectral radius:", rho)
+	}
+}
@@ -0,0 +1,164 @@
+package algnum
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"testing"
+)
+
+func lapackEigenSym(m [][]float64, n int) []float64 {
+	var mVec []float64
+	for i := 0; i < n; i++ {
+		mVec = append(mVec, m[i]...)
+	}
+	gonumM := mat.NewSymDense(n, mVec)
+
+	var eig mat.EigenSym
+	eig.Factorize(gonumM, false)
+
+	return eig.Values(nil)
+}
+
+func TestEigenSym(t *testing.T) {
+	data := [][]float64{
+		{7, 0.5},
+		{0.5, 1},
+	}
+	a, _ := InitMat(data)
+
+	vals, _, err := EigenSym(a, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{4 - math.Sqrt(9.25), 4 + math.Sqrt(9.25)}
+	if !VectsEq(vals, expected, 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expected), VectToStr(vals))
+	}
+
+	for _, n := range []int{1, 5, 60} {
+		dataN := randSymData(n, n, -10, 10)
+		matN, _ := InitMat(dataN)
+		lapack := lapackEigenSym(dataN, n)
+
+		valsN, _, err := EigenSym(matN, false)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(valsN, lapack, 1e-9) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(valsN))
+		}
+
+		valsV, vecs, err := EigenSym(matN, true)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(valsV, lapack, 1e-9) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(valsV))
+		}
+
+		vtv, _ := MatMul(TransposeMat(vecs), vecs)
+		e, _ := IdentityMat(n)
+		if !MatsEq(vtv, e, 1e-9) {
+			t.Fatal("eigenvectors aren't orthonormal")
+		}
+		av, _ := MatMul(matN, vecs)
+		for j := 0; j < n; j++ {
+			for i := 0; i < n; i++ {
+				if math.Abs(av.data[i * av.stride + j] - valsV[j] * vecs.data[i * vecs.stride + j]) > 1e-9 {
+					t.Fatalf("A * v%d != λ%d * v%d", j, j, j)
+				}
+			}
+		}
+	}
+
+	nonSymData := [][]float64{
+		{1, 2},
+		{3, 4},
+	}
+	nonSym, _ := InitMat(nonSymData)
+	if _, _, err := EigenSym(nonSym, false); err == nil {
+		t.Fatal("expected not symmetric error")
+	} else {
+		t.Log("symmetric eigensolver works correct")
+	}
+}
+
+func TestEigenSymRanges(t *testing.T) {
+	n := 10
+	a, _ := InitMatOfDim(n)
+	for i := 0; i < n; i++ {
+		a.data[i * a.stride + i] = 2
+		if i > 0 {
+			a.data[i * a.stride + i - 1] = -1
+			a.data[(i - 1) * a.stride + i] = -1
+		}
+	}
+	// eigenvalues of the 1-D Laplacian are 2 - 2cos(kπ/(n+1))
+	expected := make([]float64, n)
+	for k := 1; k <= n; k++ {
+		expected[k - 1] = 2 - 2 * math.Cos(float64(k) * math.Pi / float64(n + 1))
+	}
+
+	vals, vecs, err := EigenSymIndexRange(a, 2, 4, true)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(vals, expected[2 : 5], 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expected[2 : 5]), VectToStr(vals))
+	} else if vecs.rows != n || vecs.cols != 3 {
+		t.Fatalf("eigenvectors dims are wrong: expected %dx3, got %dx%d", n, vecs.rows, vecs.cols)
+	}
+
+	vals, _, err = EigenSymValueRange(a, 1, 3, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inRange []float64
+	for _, l := range expected {
+		if l > 1 && l <= 3 {
+			inRange = append(inRange, l)
+		}
+	}
+	if !VectsEq(vals, inRange, 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(inRange), VectToStr(vals))
+	}
+
+	// a random matrix, and one with a triple eigenvalue 1 from the
+	// orthogonal similarity Q diag(1, 1, 1, 2, ..., n - 2) Qᵀ
+	m := 40
+	randSym, _ := InitMat(randSymData(m, m, -10, 10))
+	qr, _ := QR(matOfRows(randData(m, m, -10, 10), m, m), false)
+	q := qr.Q()
+	d := newMat(m, m)
+	for i := 0; i < m; i++ {
+		d.data[i * d.stride + i] = math.Max(1, float64(i - 1))
+	}
+	qd, _ := MatMul(q, d)
+	multiple, _ := MatMul(qd, TransposeMat(q))
+	for i := 0; i < m; i++ {
+		for j := 0; j < i; j++ {
+			avg := (multiple.At(i, j) + multiple.At(j, i)) / 2
+			multiple.data[i * m + j], multiple.data[j * m + i] = avg, avg
+		}
+	}
+	for _, sym := range []*Matrix{randSym, multiple} {
+		all, _, _ := EigenSym(sym, false)
+		vals, vecs, err := EigenSymIndexRange(sym, 0, 5, true)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(vals, all[ : 6], 1e-9) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(all[ : 6]), VectToStr(vals))
+		}
+		vtv, _ := MatMul(TransposeMat(vecs), vecs)
+		e, _ := IdentityMat(6)
+		if !MatsEq(vtv, e, 1e-9) {
+			t.Fatal("selected eigenvectors aren't orthonormal")
+		}
+		av, _ := MatMul(sym, vecs)
+		for j := 0; j < 6; j++ {
+			for i := 0; i < m; i++ {
+				if math.Abs(av.At(i, j) - vals[j] * vecs.At(i, j)) > 1e-8 {
+					t.Fatalf("A * v%d != λ%d * v%d", j, j, j)
+				}
+			}
+		}
+	}
+	t.Log("eigenvalue ranges work correct")
+}
@@ -0,0 +1,83 @@
+package algnum
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Executor bounds the goroutines of the parallel routines. A call that
+// finds no free worker runs its tasks in the calling goroutine, so nested
+// parallel calls (Strassen products running Mul) can't deadlock
+type Executor struct {
+	workers int
+	// tokens of the workers besides the calling goroutine
+	sem chan struct{}
+}
+
+var defaultExecutor atomic.Value
+
+func init() {
+	defaultExecutor.Store(NewExecutor(0))
+}
+
+// workers <= 0 means GOMAXPROCS
+func NewExecutor(workers int) *Executor {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Executor{workers: workers, sem: make(chan struct{}, workers - 1)}
+}
+
+func (e *Executor) Workers() int {
+	return e.workers
+}
+
+// DefaultExecutor is used by Mul, Inverse and Strassen unless another
+// executor is given
+func DefaultExecutor() *Executor {
+	return defaultExecutor.Load().(*Executor)
+}
+
+func SetDefaultExecutor(e *Executor) {
+	if e == nil {
+		e = NewExecutor(0)
+	}
+	defaultExecutor.Store(e)
+}
+
+// runs f(0), ..., f(n - 1) and returns when all of them are done, the
+// tasks are claimed one by one by the calling goroutine and the workers
+// it could get
+func (e *Executor) ParallelFor(n int, f func(i int)) {
+	next := int64(-1)
+	work := func() {
+		for {
+			i := int(atomic.AddInt64(&next, 1))
+			if i >= n {
+				return
+			}
+			f(i)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for h := 1; h < n; h++ {
+		select {
+		case e.sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-e.sem
+					wg.Done()
+				}()
+				work()
+			}()
+			continue
+		default:
+		}
+		break
+	}
+	work()
+	wg.Wait()
+}
@@ -0,0 +1,60 @@
+package algnum
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExecutor(t *testing.T) {
+	for _, workers := range []int{1, 3, 8} {
+		e := NewExecutor(workers)
+		n := 100
+		var running, peak int64
+		counts := make([]int64, n)
+		e.ParallelFor(n, func(i int) {
+			cur := atomic.AddInt64(&running, 1)
+			for {
+				p := atomic.LoadInt64(&peak)
+				if cur <= p || atomic.CompareAndSwapInt64(&peak, p, cur) {
+					break
+				}
+			}
+			atomic.AddInt64(&counts[i], 1)
+			time.Sleep(100 * time.Microsecond)
+			atomic.AddInt64(&running, -1)
+		})
+		for i, c := range counts {
+			if c != 1 {
+				t.Fatalf("task %d ran %d times", i, c)
+			}
+		}
+		if peak > int64(workers) {
+			t.Fatalf("%d tasks ran at once on %d workers", peak, workers)
+		}
+	}
+
+	// nested calls find no free workers and run inline instead of blocking
+	e := NewExecutor(2)
+	var total int64
+	e.ParallelFor(4, func(i int) {
+		e.ParallelFor(4, func(j int) {
+			atomic.AddInt64(&total, 1)
+		})
+	})
+	if total != 16 {
+		t.Fatalf("nested tasks ran %d times, expected 16", total)
+	}
+
+	if NewExecutor(0).Workers() != runtime.GOMAXPROCS(0) {
+		t.Fatal("default parallelism isn't GOMAXPROCS")
+	}
+	defer SetDefaultExecutor(DefaultExecutor())
+	SetDefaultExecutor(NewExecutor(3))
+	if DefaultExecutor().Workers() != 3 {
+		t.Fatal("default executor isn't replaced")
+	} else {
+		t.Log("executor works correct")
+	}
+}
@@ -0,0 +1,334 @@
+package algnum
+
+import "errors"
+
+type LUFactor struct {
+	lu   [][]float64
+	perm Permutation
+	sign float64
+	n    int
+	// where the storage came from, if anywhere
+	ws   *Workspace
+	mat  *Matrix
+}
+
+type CholeskyFactor struct {
+	l [][]float64
+	n int
+}
+
+// QRFactor keeps scratch vectors for Q^T * b, so unlike the other
+// factors it mustn't be used by several goroutines at once
+type QRFactor struct {
+	qr         [][]float64
+	tau        []float64
+	rots       []givensRot
+	perm       Permutation
+	rank       int
+	rows, cols int
+	work       []float64
+}
+
+func FactorizeLU(m *Matrix) (*LUFactor, error) {
+	return FactorizeLUWithWorkspace(m, nil)
+}
+
+// the factor's storage comes from ws and goes back with Release, so
+// repeated factorizations of one size allocate only the factor itself;
+// nil ws means plain allocation
+func FactorizeLUWithWorkspace(m *Matrix, ws *Workspace) (*LUFactor, error) {
+	if !m.IsSquare() {
+		return nil, errors.New("matrix isn't square")
+	}
+	n := m.rows
+
+	if ws == nil {
+		lu, perm, sign := pivotedLU(m.rowSlices())
+		if rank := pivotedLURank(m.rowSlices(), lu); rank < n {
+			return nil, &SingularMatrixError{Rank: rank}
+		}
+		return &LUFactor{lu: lu, perm: perm, sign: sign, n: n}, nil
+	}
+
+	f := &LUFactor{}
+	if err := f.factorize(m, ws); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// f = LU of the square m with storage from ws, so a factor refactorized
+// in a loop allocates nothing
+func (f *LUFactor) factorize(m *Matrix, ws *Workspace) error {
+	n := m.rows
+	luMat := ws.mat(n, n)
+	_ = luMat.CopyFrom(m)
+	*f = LUFactor{lu: ws.rowsOf(luMat), perm: ws.intVec(n), n: n, ws: ws, mat: luMat}
+	f.sign = pivotedLUTo(f.lu, f.perm)
+
+	data := ws.rowsOf(m)
+	rank := pivotedLURank(data, f.lu)
+	ws.releaseRows(data)
+	if rank < n {
+		f.Release()
+		return &SingularMatrixError{Rank: rank}
+	}
+
+	return nil
+}
+
+// gives the storage of a factor from FactorizeLUWithWorkspace back to its
+// workspace, f mustn't be used afterwards
+func (f *LUFactor) Release() {
+	if f.ws == nil {
+		return
+	}
+	f.ws.releaseRows(f.lu)
+	f.ws.releaseInts(f.perm)
+	f.ws.releaseMat(f.mat, false)
+	f.ws, f.mat, f.lu, f.perm = nil, nil, nil, nil
+}
+
+func (f *LUFactor) Dim() int {
+	return f.n
+}
+
+func (f *LUFactor) SolveTo(dst, b []float64) error {
+	if len(b) != f.n || len(dst) != f.n {
+		return errors.New("factor and vector dims don't match")
+	}
+	if f.n > 0 && &dst[0] == &b[0] {
+		return errors.New("destination and free element overlap")
+	}
+
+	pivotedLUSolve(f.lu, f.perm, b, dst)
+
+	return nil
+}
+
+func (f *LUFactor) Solve(b []float64) ([]float64, error) {
+	x := make([]float64, f.n)
+	if err := f.SolveTo(x, b); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (f *LUFactor) SolveMany(b *Matrix) (*Matrix, error) {
+	return solveMany(f.n, f.n, b, f.SolveTo)
+}
+
+func (f *LUFactor) Det() (float64, error) {
+	res := f.sign
+	for i := 0; i < f.n; i++ {
+		res *= f.lu[i][i]
+	}
+	return res, nil
+}
+
+func (f *LUFactor) Inverse() (*Matrix, error) {
+	e, _ := IdentityMat(f.n)
+	return f.SolveMany(e)
+}
+
+func FactorizeCholesky(m *Matrix) (*CholeskyFactor, error) {
+	if !m.IsSymmetric() {
+		return nil, errors.New("matrix isn't symmetric")
+	}
+
+	l, err := choleskyDecomp(m.rowSlices())
+	if err != nil {
+		return nil, err
+	}
+
+	return &CholeskyFactor{l: l, n: m.rows}, nil
+}
+
+func (f *CholeskyFactor) Dim() int {
+	return f.n
+}
+
+func (f *CholeskyFactor) L() *Matrix {
+	return matOfRows(copy2dSlice(f.l), f.n, f.n)
+}
+
+func (f *CholeskyFactor) SolveTo(dst, b []float64) error {
+	if len(b) != f.n || len(dst) != f.n {
+		return errors.New("factor and vector dims don't match")
+	}
+
+	n := f.n
+	for i := 0; i < n; i++ {
+		sum := b[i]
+		for k := 0; k < i; k++ {
+			sum -= f.l[i][k] * dst[k]
+		}
+		dst[i] = sum / f.l[i][i]
+	}
+	for i := n - 1; i >= 0; i-- {
+		sum := dst[i]
+		for k := i + 1; k < n; k++ {
+			sum -= f.l[k][i] * dst[k]
+		}
+		dst[i] = sum / f.l[i][i]
+	}
+
+	return nil
+}
+
+func (f *CholeskyFactor) Solve(b []float64) ([]float64, error) {
+	x := make([]float64, f.n)
+	if err := f.SolveTo(x, b); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (f *CholeskyFactor) SolveMany(b *Matrix) (*Matrix, error) {
+	return solveMany(f.n, f.n, b, f.SolveTo)
+}
+
+func (f *CholeskyFactor) Det() (float64, error) {
+	res := float64(1)
+	for i := 0; i < f.n; i++ {
+		res *= f.l[i][i] * f.l[i][i]
+	}
+	return res, nil
+}
+
+func (f *CholeskyFactor) Inverse() (*Matrix, error) {
+	e, _ := IdentityMat(f.n)
+	return f.SolveMany(e)
+}
+
+func FactorizeQR(m *Matrix) (*QRFactor, error) {
+	if m.rows < m.cols {
+		return nil, errors.New("matrix has more columns than rows")
+	}
+
+	f, err := QR(m, false)
+	if err != nil {
+		return nil, err
+	}
+	if f.rank < m.cols {
+		return nil, &SingularMatrixError{Rank: f.rank}
+	}
+
+	return f, nil
+}
+
+func (f *QRFactor) Dims() (int, int) {
+	return f.rows, f.cols
+}
+
+// for a tall matrix dst gets the least squares solution, for a rank
+// deficient one factorized with column pivoting the basic solution
+// with zeros in the trailing pivot positions
+func (f *QRFactor) SolveTo(dst, b []float64) error {
+	if len(b) != f.rows || len(dst) != f.cols {
+		return errors.New("factor and vector dims don't match")
+	}
+	k := intMin(f.rows, f.cols)
+	if f.perm == nil && f.rank < k {
+		return &SingularMatrixError{Rank: f.rank}
+	}
+	r := f.rank
+	if f.perm == nil {
+		r = k
+	}
+
+	qtb := f.work[ : f.rows]
+	copy(qtb, b)
+	f.applyQT(qtb)
+
+	z := dst
+	if f.perm != nil {
+		z = f.work[f.rows : ]
+	}
+	for i := r; i < f.cols; i++ {
+		z[i] = 0
+	}
+	for i := r - 1; i >= 0; i-- {
+		sum := qtb[i]
+		for j := i + 1; j < r; j++ {
+			sum -= f.qr[i][j] * z[j]
+		}
+		z[i] = sum / f.qr[i][i]
+	}
+
+	if f.perm != nil {
+		for i, pi := range f.perm {
+			dst[pi] = z[i]
+		}
+	}
+
+	return nil
+}
+
+func (f *QRFactor) Solve(b []float64) ([]float64, error) {
+	x := make([]float64, f.cols)
+	if err := f.SolveTo(x, b); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (f *QRFactor) SolveMany(b *Matrix) (*Matrix, error) {
+	return solveMany(f.rows, f.cols, b, f.SolveTo)
+}
+
+func (f *QRFactor) Det() (float64, error) {
+	if f.rows != f.cols {
+		return -1, errors.New("matrix isn't square")
+	}
+
+	res := float64(1)
+	for i := 0; i < f.cols; i++ {
+		res *= f.qr[i][i]
+		if f.tau != nil && f.tau[i] != 0 {
+			res = -res
+		}
+	}
+	if f.perm != nil {
+		res *= f.perm.Sign()
+	}
+	return res, nil
+}
+
+func (f *QRFactor) Inverse() (*Matrix, error) {
+	if f.rows != f.cols {
+		return nil, errors.New("matrix isn't square")
+	}
+	if f.rank < f.rows {
+		return nil, &SingularMatrixError{Rank: f.rank}
+	}
+
+	e, _ := IdentityMat(f.rows)
+	return f.SolveMany(e)
+}
+
+func solveMany(rows, cols int, b *Matrix, solveTo func(dst, b []float64) error) (*Matrix, error) {
+	if b.rows != rows {
+		return nil, errors.New("factor and matrix dims don't match")
+	}
+
+	res, err := InitMatOfDims(cols, b.cols)
+	if err != nil {
+		return nil, err
+	}
+
+	bj, xj := make([]float64, rows), make([]float64, cols)
+	for j := 0; j < b.cols; j++ {
+		for i := 0; i < rows; i++ {
+			bj[i] = b.data[i * b.stride + j]
+		}
+		if err := solveTo(xj, bj); err != nil {
+			return nil, err
+		}
+		for i := 0; i < cols; i++ {
+			res.data[i * res.stride + j] = xj[i]
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,168 @@
+package algnum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLUFactor(t *testing.T) {
+	dataN := randData(100, 100, 1, 10)
+	matN, _ := InitMat(dataN)
+
+	f, err := FactorizeLU(matN)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k := 0; k < 3; k++ {
+		fN := randFree(100, 1, 10)
+		lapack, err := lapackSolve(dataN, len(dataN), fN)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := f.Solve(fN)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(res, lapack, Epsilon) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(res))
+		}
+	}
+
+	d, _ := f.Det()
+	lapackD := lapackDet(dataN, len(dataN))
+	if math.Abs(d - lapackD) > 1e-8 * math.Abs(lapackD) {
+		t.Fatalf("det is wrong: expected %e, got %e", lapackD, d)
+	}
+
+	inv, err := f.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, _ := IdentityMat(100)
+	check, _ := MatMul(inv, matN)
+	if !MatsEq(check, e, 1e-8) {
+		sub, _ := MatsSub(check, e)
+		t.Fatalf("inverse is wrong: inv * A - E norm %0.15f", sub.Norm(EuclideanNorm))
+	}
+
+	x, dst := randFree(100, 1, 10), make([]float64, 100)
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = f.SolveTo(dst, x)
+	})
+	if allocs != 0 {
+		t.Fatalf("solve allocates: %f allocs per run", allocs)
+	} else {
+		t.Log("LU factor works correct")
+	}
+}
+
+func TestCholeskyFactor(t *testing.T) {
+	data := randDiagSym(100, 100, 1, 100)
+	mat, _ := InitMat(data)
+
+	f, err := FactorizeCholesky(mat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := f.L()
+	llt, _ := MatMul(l, TransposeMat(l))
+	if !MatsEq(llt, mat, 1e-9) {
+		sub, _ := MatsSub(llt, mat)
+		t.Fatalf("result is wrong: LL^T - A norm %0.15f", sub.Norm(EuclideanNorm))
+	}
+
+	b, _ := InitMat(randData(100, 100, 1, 100))
+	x, err := f.SolveMany(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check, _ := MatMul(mat, x)
+	if !MatsEq(check, b, 1e-8) {
+		t.Fatal("solve many is wrong")
+	}
+
+	d, _ := f.Det()
+	lapackD := lapackDet(data, len(data))
+	if math.Abs(d - lapackD) > 1e-8 * math.Abs(lapackD) {
+		t.Fatalf("det is wrong: expected %e, got %e", lapackD, d)
+	}
+
+	notPosData := [][]float64{
+		{1, 2},
+		{2, 1},
+	}
+	notPos, _ := InitMat(notPosData)
+	if _, err := FactorizeCholesky(notPos); err == nil {
+		t.Fatal("expected not positive definite error")
+	}
+
+	fN, dst := randFree(100, 1, 10), make([]float64, 100)
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = f.SolveTo(dst, fN)
+	})
+	if allocs != 0 {
+		t.Fatalf("solve allocates: %f allocs per run", allocs)
+	} else {
+		t.Log("cholesky factor works correct")
+	}
+}
+
+func TestQRFactor(t *testing.T) {
+	dataN := randData(50, 50, 1, 10)
+	matN, _ := InitMat(dataN)
+	fN := randFree(50, 1, 10)
+
+	f, err := FactorizeQR(matN)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lapack, err := lapackSolve(dataN, len(dataN), fN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := f.Solve(fN)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(res, lapack, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(res))
+	}
+
+	d, _ := f.Det()
+	lapackD := lapackDet(dataN, len(dataN))
+	if math.Abs(d - lapackD) > 1e-8 * math.Abs(lapackD) {
+		t.Fatalf("det is wrong: expected %e, got %e", lapackD, d)
+	}
+
+	tallData := [][]float64{
+		{1, 1},
+		{1, 2},
+		{1, 3},
+		{1, 4},
+	}
+	tall, _ := InitMat(tallData)
+	tallF := []float64{6, 5, 7, 10}
+
+	ft, err := FactorizeQR(tall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resT, err := ft.Solve(tallF)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(resT, []float64{3.5, 1.4}, Epsilon) {
+		t.Fatalf("least squares result is wrong: expected\n %s,\ngot\n %s", VectToStr([]float64{3.5, 1.4}), VectToStr(resT))
+	}
+
+	dst := make([]float64, 2)
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = ft.SolveTo(dst, tallF)
+	})
+	if allocs != 0 {
+		t.Fatalf("solve allocates: %f allocs per run", allocs)
+	} else {
+		t.Log("QR factor works correct")
+	}
+}
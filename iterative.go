@@ -0,0 +1,373 @@
+package algnum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	RelativeResidual = iota
+	AbsoluteResidual
+)
+
+const (
+	LeftPrecond = iota
+	RightPrecond
+)
+
+const (
+	iterMaxIter = 10000
+	iterTol     = 1e-10
+	// residual growth over the initial one treated as divergence
+	divergenceFactor = 1e10
+)
+
+// zero fields take defaults: Tol = 1e-10, MaxIter = 10000,
+// Criterion = RelativeResidual (||f - Ax|| / ||f||), a zero initial guess
+// and no preconditioning; Precond is used by the Krylov methods only and
+// PrecondSide by GMRES and BiCGSTAB, CG always preconditions symmetrically.
+// Scratch vectors come from Workspace, nil means a fresh one per call
+type SolveOptions struct {
+	Tol         float64
+	MaxIter     int
+	Criterion   int
+	X0          []float64
+	Precond     Preconditioner
+	PrecondSide int
+	Workspace   *Workspace
+}
+
+type SolveResult struct {
+	X          []float64
+	Iterations int
+	Residual   float64
+	History    []float64
+}
+
+type ErrNotConverged struct {
+	Iterations int
+	Residual   float64
+}
+
+func (e *ErrNotConverged) Error() string {
+	return fmt.Sprintf("iterations didn't converge: residual %e after %d iterations", e.Residual, e.Iterations)
+}
+
+type ErrDiverged struct {
+	Iterations int
+	Residual   float64
+}
+
+func (e *ErrDiverged) Error() string {
+	return fmt.Sprintf("iterations diverged: residual %e after %d iterations", e.Residual, e.Iterations)
+}
+
+func (o *SolveOptions) withDefaults(n int) (*SolveOptions, error) {
+	res := SolveOptions{}
+	if o != nil {
+		res = *o
+	}
+	if res.Tol <= 0 {
+		res.Tol = iterTol
+	}
+	if res.MaxIter <= 0 {
+		res.MaxIter = iterMaxIter
+	}
+	if res.Criterion != RelativeResidual && res.Criterion != AbsoluteResidual {
+		return nil, errors.New("unknown residual criterion")
+	}
+	if res.PrecondSide != LeftPrecond && res.PrecondSide != RightPrecond {
+		return nil, errors.New("unknown preconditioning side")
+	}
+	if res.X0 != nil && len(res.X0) != n {
+		return nil, errors.New("matrix and initial guess dims don't match")
+	}
+	if res.Workspace == nil {
+		res.Workspace = NewWorkspace()
+	}
+	return &res, nil
+}
+
+func (o *SolveOptions) residualScale(f []float64) float64 {
+	if o.Criterion == RelativeResidual {
+		if fNorm := VecNorm(f, EuclideanNorm); fNorm != 0 {
+			return fNorm
+		}
+	}
+	return 1
+}
+
+func diverged(r, r0 float64) bool {
+	return math.IsNaN(r) || math.IsInf(r, 0) || r > divergenceFactor * r0
+}
+
+// runs sweep, which updates x in place, until the residual criterion holds;
+// on cancellation the last iterate is returned along with ctx.Err()
+func iterate(ctx context.Context, a LinearOperator, f []float64, o *SolveOptions, sweep func(x []float64)) (*SolveResult, error) {
+	n := len(f)
+	x, ax := make([]float64, n), o.Workspace.vec(n)
+	defer o.Workspace.release(ax)
+	if o.X0 != nil {
+		copy(x, o.X0)
+	}
+
+	scale := o.residualScale(f)
+	residual := func() float64 {
+		a.MulVec(ax, x)
+		var sum float64
+		for i := 0; i < n; i++ {
+			r := f[i] - ax[i]
+			sum += r * r
+		}
+		return math.Sqrt(sum) / scale
+	}
+
+	r0 := residual()
+	res := &SolveResult{X: x, Residual: r0, History: []float64{r0}}
+	if r0 <= o.Tol {
+		return res, nil
+	}
+
+	for iter := 1; iter <= o.MaxIter; iter++ {
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+		sweep(x)
+		r := residual()
+		res.Iterations, res.Residual = iter, r
+		res.History = append(res.History, r)
+
+		if diverged(r, r0) {
+			return res, &ErrDiverged{iter, r}
+		}
+		if r <= o.Tol {
+			return res, nil
+		}
+	}
+
+	return res, &ErrNotConverged{o.MaxIter, res.Residual}
+}
+
+func SOR(a ElementAccessor, f []float64, omega float64, opts *SolveOptions) (*SolveResult, error) {
+	return SORCtx(context.Background(), a, f, omega, opts)
+}
+
+func SORCtx(ctx context.Context, a ElementAccessor, f []float64, omega float64, opts *SolveOptions) (*SolveResult, error) {
+	a = byRows(a)
+	o, err := checkRelaxation(a, f, omega, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(f)
+	row := o.Workspace.vec(n)
+	defer o.Workspace.release(row)
+	return iterate(ctx, AsOperator(a), f, o, func(x []float64) {
+		for i := 0; i < n; i++ {
+			relax(a, i, f, x, row, omega)
+		}
+	})
+}
+
+// forward SOR sweep followed by a backward one
+func SSOR(a ElementAccessor, f []float64, omega float64, opts *SolveOptions) (*SolveResult, error) {
+	return SSORCtx(context.Background(), a, f, omega, opts)
+}
+
+func SSORCtx(ctx context.Context, a ElementAccessor, f []float64, omega float64, opts *SolveOptions) (*SolveResult, error) {
+	a = byRows(a)
+	o, err := checkRelaxation(a, f, omega, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(f)
+	row := o.Workspace.vec(n)
+	defer o.Workspace.release(row)
+	return iterate(ctx, AsOperator(a), f, o, func(x []float64) {
+		for i := 0; i < n; i++ {
+			relax(a, i, f, x, row, omega)
+		}
+		for i := n - 1; i >= 0; i-- {
+			relax(a, i, f, x, row, omega)
+		}
+	})
+}
+
+func checkRelaxation(a ElementAccessor, f []float64, omega float64, opts *SolveOptions) (*SolveOptions, error) {
+	if err := checkOperator(a, f); err != nil {
+		return nil, err
+	}
+	if omega <= 0 || omega >= 2 {
+		return nil, errors.New("relaxation factor isn't in (0, 2)")
+	}
+	for i := range f {
+		if a.At(i, i) == 0 {
+			return nil, errors.New("matrix has zero diagonal element")
+		}
+	}
+	return opts.withDefaults(len(f))
+}
+
+func relax(a ElementAccessor, i int, f, x, row []float64, omega float64) {
+	sum, aii := rowSplitDot(a, i, x, row)
+	x[i] = (1 - omega) * x[i] + omega * (f[i] - sum) / aii
+}
+
+// ω_opt = 2 / (1 + sqrt(1 - ρ²)) with ρ the spectral radius of the Jacobi
+// iteration matrix; optimal for consistently ordered matrices only.
+// ρ is taken from the extreme eigenvalues 1 ± ρ of D^(-1/2) A D^(-1/2),
+// applied as an operator, so a sparse a isn't made dense. Only symmetric
+// matrices with positive diagonal are supported: the symmetric eigensolver
+// needs a symmetric D^(-1/2) A D^(-1/2), so consistently ordered but
+// nonsymmetric matrices are rejected
+func OptimalOmega(a ElementAccessor) (float64, error) {
+	return OptimalOmegaCtx(context.Background(), a)
+}
+
+func OptimalOmegaCtx(ctx context.Context, a ElementAccessor) (float64, error) {
+	n, _ := a.Dims()
+	if n == 0 {
+		return 0, errors.New("matrix is empty")
+	}
+	if !isSymmetric(a) {
+		return 0, errors.New("only symmetric matrices are supported")
+	}
+
+	d := make([]float64, n)
+	for i := 0; i < n; i++ {
+		aii := a.At(i, i)
+		if aii <= 0 {
+			return 0, errors.New("matrix has non-positive diagonal element")
+		}
+		d[i] = 1 / math.Sqrt(aii)
+	}
+	scaled := &diagScaledOp{AsOperator(a), d, make([]float64, n)}
+
+	min, max, err := MyMinMaxEigenvaluesCtx(ctx, scaled)
+	if err != nil {
+		return 0, err
+	}
+
+	rho := math.Max(max - 1, 1 - min)
+	if rho >= 1 {
+		return 0, errors.New("jacobi iteration doesn't converge")
+	}
+
+	return 2 / (1 + math.Sqrt(1 - rho * rho)), nil
+}
+
+// D A D for a diagonal D, applied without forming the product
+type diagScaledOp struct {
+	a    LinearOperator
+	d    []float64
+	work []float64
+}
+
+func (op *diagScaledOp) Dims() (int, int) {
+	return op.a.Dims()
+}
+
+func (op *diagScaledOp) MulVec(dst, x []float64) {
+	for i, di := range op.d {
+		op.work[i] = di * x[i]
+	}
+	op.a.MulVec(dst, op.work)
+	for i, di := range op.d {
+		dst[i] *= di
+	}
+}
+
+func (op *diagScaledOp) MulVecTrans(dst, x []float64) {
+	for i, di := range op.d {
+		op.work[i] = di * x[i]
+	}
+	op.a.MulVecTrans(dst, op.work)
+	for i, di := range op.d {
+		dst[i] *= di
+	}
+}
+
+// for symmetric positive definite a, Precond has to be symmetric positive definite too
+func ConjugateGradient(a LinearOperator, f []float64, opts *SolveOptions) (*SolveResult, error) {
+	return ConjugateGradientCtx(context.Background(), a, f, opts)
+}
+
+func ConjugateGradientCtx(ctx context.Context, a LinearOperator, f []float64, opts *SolveOptions) (*SolveResult, error) {
+	if err := checkOperator(a, f); err != nil {
+		return nil, err
+	}
+	n := len(f)
+	o, err := opts.withDefaults(n)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := o.Workspace
+	x, r, z, p, ap := make([]float64, n), ws.vec(n), ws.vec(n), ws.vec(n), ws.vec(n)
+	defer releaseVecs(ws, r, z, p, ap)
+	if o.X0 != nil {
+		copy(x, o.X0)
+	}
+	a.MulVec(ap, x)
+	for i := 0; i < n; i++ {
+		r[i] = f[i] - ap[i]
+	}
+
+	scale := o.residualScale(f)
+	r0 := VecNorm(r, EuclideanNorm) / scale
+	res := &SolveResult{X: x, Residual: r0, History: []float64{r0}}
+	if r0 <= o.Tol {
+		return res, nil
+	}
+
+	precond := func() {
+		if o.Precond != nil {
+			o.Precond.Apply(z, r)
+		} else {
+			copy(z, r)
+		}
+	}
+	precond()
+	copy(p, z)
+	rz := dot(r, z)
+
+	for iter := 1; iter <= o.MaxIter; iter++ {
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+
+		a.MulVec(ap, p)
+		pap := dot(p, ap)
+		if pap <= 0 {
+			return res, errors.New("matrix isn't positive definite")
+		}
+		alpha := rz / pap
+		for i := 0; i < n; i++ {
+			x[i] += alpha * p[i]
+			r[i] -= alpha * ap[i]
+		}
+
+		rNorm := VecNorm(r, EuclideanNorm) / scale
+		res.Iterations, res.Residual = iter, rNorm
+		res.History = append(res.History, rNorm)
+		if diverged(rNorm, r0) {
+			return res, &ErrDiverged{iter, rNorm}
+		}
+		if rNorm <= o.Tol {
+			return res, nil
+		}
+
+		precond()
+		rzNext := dot(r, z)
+		beta := rzNext / rz
+		rz = rzNext
+		for i := 0; i < n; i++ {
+			p[i] = z[i] + beta * p[i]
+		}
+	}
+
+	return res, &ErrNotConverged{o.MaxIter, res.Residual}
+}
@@ -0,0 +1,273 @@
+package algnum
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestIterativeOptions(t *testing.T) {
+	data := [][]float64{
+		{3, 2, 1},
+		{1, 3, 2},
+		{1, 2, 4},
+	}
+	mat, _ := InitMat(data)
+	f := []float64{1, 4, 2}
+	expectedRes := []float64{-float64(14) / float64(19), float64(32) / float64(19), -float64(3) / float64(19)}
+
+	for _, solve := range []func(ElementAccessor, []float64, *SolveOptions) (*SolveResult, error){Jacobi, Seidel} {
+		res, err := solve(mat, f, &SolveOptions{Tol: 1e-12, Criterion: AbsoluteResidual})
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(expectedRes, res.X, 1e-10) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes), VectToStr(res.X))
+		} else if res.Residual > 1e-12 || len(res.History) != res.Iterations + 1 {
+			t.Fatalf("result is wrong: residual %e, %d iterations, history of %d", res.Residual, res.Iterations, len(res.History))
+		}
+
+		res, err = solve(mat, f, &SolveOptions{X0: expectedRes})
+		if err != nil {
+			t.Fatal(err)
+		} else if res.Iterations != 0 {
+			t.Fatalf("expected no iterations from the exact guess, got %d", res.Iterations)
+		}
+
+		res, err = solve(mat, f, &SolveOptions{MaxIter: 2})
+		var notConverged *ErrNotConverged
+		if !errors.As(err, &notConverged) {
+			t.Fatalf("expected not converged error, got %v", err)
+		} else if notConverged.Iterations != 2 || res.Iterations != 2 || len(res.X) != 3 {
+			t.Fatalf("result is wrong: %d iterations reported", notConverged.Iterations)
+		}
+	}
+
+	t.Log("iteration options work correct")
+}
+
+func TestIterativeDiverged(t *testing.T) {
+	// passes the row dominance check, but the zero diagonal yields NaN
+	data := [][]float64{
+		{0, 0},
+		{0, 1},
+	}
+	mat, _ := InitMat(data)
+	f := []float64{1, 1}
+
+	for _, solve := range []func(ElementAccessor, []float64, *SolveOptions) (*SolveResult, error){Jacobi, Seidel} {
+		_, err := solve(mat, f, nil)
+		var diverged *ErrDiverged
+		if !errors.As(err, &diverged) {
+			t.Fatalf("expected diverged error, got %v", err)
+		}
+	}
+
+	// singular, eigenvalues 0 and 2: too big a step blows up, the optimal
+	// one stalls on the null space component of f
+	singular, _ := InitMat([][]float64{{1, 1}, {1, 1}})
+	g := []float64{1, 2}
+	var diverged *ErrDiverged
+	if _, err := FixedPointIterationWithT(singular, g, 1.5, nil); !errors.As(err, &diverged) {
+		t.Fatalf("expected diverged error, got %v", err)
+	}
+	var notConverged *ErrNotConverged
+	if _, err := FixedPointIteration(singular, g, &SolveOptions{MaxIter: 100}); !errors.As(err, &notConverged) {
+		t.Fatalf("expected not converged error, got %v", err)
+	}
+
+	t.Log("divergence detection works correct")
+}
+
+func TestIterativeCtx(t *testing.T) {
+	dataN := randDiagDominantData(200, 200, 1, 100)
+	matN, _ := InitMat(dataN)
+	fN := randFree(200, 1, 1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, solve := range []func(context.Context, ElementAccessor, []float64, *SolveOptions) (*SolveResult, error){JacobiCtx, SeidelCtx} {
+		res, err := solve(ctx, matN, fN, nil)
+		if err != context.Canceled {
+			t.Fatalf("expected canceled error, got %v", err)
+		} else if res == nil || len(res.X) != 200 || len(res.History) != 1 {
+			t.Fatal("expected partial result along with canceled error")
+		}
+	}
+
+	t.Log("iteration cancellation works correct")
+}
+
+func TestSOR(t *testing.T) {
+	n := 50
+	data := tridiagData(n)
+	a, _ := InitMat(data)
+	f := randFree(n, 1, 10)
+
+	omega, err := OptimalOmega(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOmega := 2 / (1 + math.Sin(math.Pi / float64(n + 1)))
+	if math.Abs(omega - expectedOmega) > 1e-3 {
+		t.Fatalf("result is wrong: expected ω %0.15f, got %0.15f", expectedOmega, omega)
+	}
+	if sparseOmega, err := OptimalOmega(MatToCSR(a)); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(sparseOmega - omega) > 1e-9 {
+		t.Fatalf("result is wrong for CSR: expected ω %0.15f, got %0.15f", omega, sparseOmega)
+	}
+	// consistently ordered but not symmetric
+	nonsym := tridiagData(4)
+	nonsym[0][1] = 2
+	if _, err := OptimalOmega(matOfRows(nonsym, 4, 4)); err == nil {
+		t.Fatal("expected symmetric matrices only error")
+	}
+
+	lapack, err := lapackSolve(data, n, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seidel, err := Seidel(a, f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sor, err := SOR(a, f, omega, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, sor.X, 1e-6) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(sor.X))
+	}
+	ssor, err := SSOR(a, f, 1.5, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, ssor.X, 1e-6) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(ssor.X))
+	}
+
+	if sor.Iterations >= seidel.Iterations {
+		t.Fatalf("expected SOR to beat Seidel, got %d vs. %d iterations", sor.Iterations, seidel.Iterations)
+	}
+	t.Logf("iterations: seidel %d, sor(ω = %0.4f) %d, ssor(ω = 1.5) %d", seidel.Iterations, omega, sor.Iterations, ssor.Iterations)
+
+	one, err := SOR(a, f, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if one.Iterations != seidel.Iterations {
+		t.Fatalf("expected SOR with ω = 1 to match Seidel, got %d vs. %d iterations", one.Iterations, seidel.Iterations)
+	}
+
+	if _, err := SOR(a, f, 2, nil); err == nil {
+		t.Fatal("expected relaxation factor error")
+	} else {
+		t.Log("sor works correct")
+	}
+}
+
+// matrix-free 5-point laplacian on a k x k grid
+type laplacian2d struct {
+	k int
+}
+
+func (l laplacian2d) Dims() (int, int) {
+	return l.k * l.k, l.k * l.k
+}
+
+func (l laplacian2d) MulVec(dst, x []float64) {
+	k := l.k
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			v := 4 * x[i * k + j]
+			if i > 0 {
+				v -= x[(i - 1) * k + j]
+			}
+			if i < k - 1 {
+				v -= x[(i + 1) * k + j]
+			}
+			if j > 0 {
+				v -= x[i * k + j - 1]
+			}
+			if j < k - 1 {
+				v -= x[i * k + j + 1]
+			}
+			dst[i * k + j] = v
+		}
+	}
+}
+
+func (l laplacian2d) MulVecTrans(dst, x []float64) {
+	l.MulVec(dst, x)
+}
+
+func laplacian2dData(k int) [][]float64 {
+	n := k * k
+	data := init2dSlice(n, n)
+	e, col := make([]float64, n), make([]float64, n)
+	for j := 0; j < n; j++ {
+		e[j] = 1
+		laplacian2d{k}.MulVec(col, e)
+		e[j] = 0
+		for i := 0; i < n; i++ {
+			data[i][j] = col[i]
+		}
+	}
+	return data
+}
+
+func TestConjugateGradient(t *testing.T) {
+	k := 12
+	n := k * k
+	data := laplacian2dData(k)
+	a, _ := InitMat(data)
+	f := randFree(n, 1, 10)
+
+	lapack, err := lapackSolve(data, n, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := ConjugateGradient(a, f, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, plain.X, 1e-8) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(plain.X))
+	}
+
+	free, err := ConjugateGradient(laplacian2d{k}, f, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, free.X, 1e-8) || free.Iterations != plain.Iterations {
+		t.Fatalf("result is wrong: matrix-free operator took %d iterations, dense %d", free.Iterations, plain.Iterations)
+	}
+
+	jacobi, _ := InitJacobiPreconditioner(a)
+	ssor, _ := InitSSORPreconditioner(a, 1.5)
+	ic, err := InitICPreconditioner(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, p := range map[string]Preconditioner{"jacobi": jacobi, "ssor": ssor, "ic": ic} {
+		res, err := ConjugateGradient(laplacian2d{k}, f, &SolveOptions{Precond: p})
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(lapack, res.X, 1e-8) {
+			t.Fatalf("result is wrong with %s preconditioner: expected\n %s,\ngot\n %s", name, VectToStr(lapack), VectToStr(res.X))
+		} else if res.Iterations > plain.Iterations {
+			t.Fatalf("%s preconditioner slowed CG down: %d vs. %d iterations", name, res.Iterations, plain.Iterations)
+		}
+		t.Logf("%s preconditioned CG: %d iterations, plain CG: %d", name, res.Iterations, plain.Iterations)
+	}
+
+	indefData := [][]float64{
+		{1, 0},
+		{0, -1},
+	}
+	indef, _ := InitMat(indefData)
+	if _, err := ConjugateGradient(indef, []float64{1, 1}, nil); err == nil {
+		t.Fatal("expected not positive definite error")
+	} else {
+		t.Log("conjugate gradient works correct")
+	}
+}
@@ -0,0 +1,307 @@
+package algnum
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+const gmresRestart = 30
+
+// M^(-1) * A, used to run left preconditioned methods unpreconditioned
+type leftPrecondOp struct {
+	a    mulVecer
+	m    Preconditioner
+	work []float64
+}
+
+func (op *leftPrecondOp) MulVec(dst, x []float64) {
+	op.a.MulVec(op.work, x)
+	op.m.Apply(dst, op.work)
+}
+
+// with left preconditioning the system M^(-1) A x = M^(-1) f is solved
+// unpreconditioned, so residuals are the preconditioned ones
+func preconditionSide(a LinearOperator, f []float64, o *SolveOptions) (mulVecer, []float64, *SolveOptions) {
+	if o.Precond == nil || o.PrecondSide != LeftPrecond {
+		return a, f, o
+	}
+	n := len(f)
+	pf := o.Workspace.vec(n)
+	o.Precond.Apply(pf, f)
+
+	res := *o
+	res.Precond = nil
+	return &leftPrecondOp{a, o.Precond, o.Workspace.vec(n)}, pf, &res
+}
+
+// gives back what preconditionSide took from the workspace
+func releasePrecondSide(op mulVecer, f []float64, o *SolveOptions) {
+	if lp, ok := op.(*leftPrecondOp); ok {
+		releaseVecs(o.Workspace, lp.work, f)
+	}
+}
+
+// restarted GMRES(m), restart <= 0 means min(n, 30); Iterations counts
+// Arnoldi steps over all cycles and History holds the residual estimates
+// from the Givens least squares after each of them
+func GMRES(a LinearOperator, f []float64, restart int, opts *SolveOptions) (*SolveResult, error) {
+	return GMRESCtx(context.Background(), a, f, restart, opts)
+}
+
+func GMRESCtx(ctx context.Context, a LinearOperator, f []float64, restart int, opts *SolveOptions) (*SolveResult, error) {
+	if err := checkOperator(a, f); err != nil {
+		return nil, err
+	}
+	n := len(f)
+	o, err := opts.withDefaults(n)
+	if err != nil {
+		return nil, err
+	}
+	op, f, o := preconditionSide(a, f, o)
+	defer releasePrecondSide(op, f, o)
+
+	m := restart
+	if m <= 0 {
+		m = intMin(n, gmresRestart)
+	}
+
+	ws := o.Workspace
+	x, w, z := make([]float64, n), ws.vec(n), ws.vec(n)
+	if o.X0 != nil {
+		copy(x, o.X0)
+	}
+	vMat, hMat := ws.mat(m + 1, n), ws.mat(m + 1, m)
+	v, h := ws.rowsOf(vMat), ws.rowsOf(hMat)
+	cs, sn, g, y := ws.vec(m), ws.vec(m), ws.vec(m + 1), ws.vec(m)
+	defer releaseVecs(ws, w, z, cs, sn, g, y)
+	defer ws.releaseMat(vMat, false)
+	defer ws.releaseMat(hMat, false)
+	defer ws.releaseRows(v)
+	defer ws.releaseRows(h)
+
+	scale := o.residualScale(f)
+	res := &SolveResult{X: x}
+
+	for {
+		op.MulVec(w, x)
+		for i := 0; i < n; i++ {
+			v[0][i] = f[i] - w[i]
+		}
+		beta := VecNorm(v[0], EuclideanNorm)
+		res.Residual = beta / scale
+		if res.History == nil {
+			res.History = []float64{res.Residual}
+		}
+		if beta / scale <= o.Tol {
+			return res, nil
+		}
+		if res.Iterations >= o.MaxIter {
+			return res, &ErrNotConverged{res.Iterations, res.Residual}
+		}
+		for i := 0; i < n; i++ {
+			v[0][i] /= beta
+		}
+		for i := range g {
+			g[i] = 0
+		}
+		g[0] = beta
+
+		k := 0
+		converged := false
+		for j := 0; j < m && res.Iterations < o.MaxIter; j++ {
+			if ctx.Err() != nil {
+				break
+			}
+
+			if o.Precond != nil {
+				o.Precond.Apply(z, v[j])
+				op.MulVec(w, z)
+			} else {
+				op.MulVec(w, v[j])
+			}
+			for i := 0; i <= j; i++ {
+				h[i][j] = dot(w, v[i])
+				for l := 0; l < n; l++ {
+					w[l] -= h[i][j] * v[i][l]
+				}
+			}
+			hNext := VecNorm(w, EuclideanNorm)
+			if hNext != 0 {
+				for l := 0; l < n; l++ {
+					v[j + 1][l] = w[l] / hNext
+				}
+			}
+
+			for i := 0; i < j; i++ {
+				hi := cs[i] * h[i][j] + sn[i] * h[i + 1][j]
+				h[i + 1][j] = -sn[i] * h[i][j] + cs[i] * h[i + 1][j]
+				h[i][j] = hi
+			}
+			d := math.Hypot(h[j][j], hNext)
+			if d == 0 {
+				return res, errors.New("gmres breakdown: matrix is singular")
+			}
+			cs[j], sn[j] = h[j][j] / d, hNext / d
+			h[j][j] = d
+			g[j + 1] = -sn[j] * g[j]
+			g[j] = cs[j] * g[j]
+
+			k = j + 1
+			res.Iterations++
+			res.Residual = math.Abs(g[j + 1]) / scale
+			res.History = append(res.History, res.Residual)
+
+			if math.IsNaN(res.Residual) {
+				return res, &ErrDiverged{res.Iterations, res.Residual}
+			}
+			// hNext == 0 means the Krylov subspace is invariant, the solution is exact
+			if res.Residual <= o.Tol || hNext == 0 {
+				converged = true
+				break
+			}
+		}
+
+		// y = H^(-1) g, x += V y (or M^(-1) V y)
+		for i := k - 1; i >= 0; i-- {
+			sum := g[i]
+			for l := i + 1; l < k; l++ {
+				sum -= h[i][l] * y[l]
+			}
+			y[i] = sum / h[i][i]
+		}
+		for l := 0; l < n; l++ {
+			w[l] = 0
+		}
+		for i := 0; i < k; i++ {
+			for l := 0; l < n; l++ {
+				w[l] += y[i] * v[i][l]
+			}
+		}
+		if o.Precond != nil {
+			o.Precond.Apply(z, w)
+			copy(w, z)
+		}
+		for l := 0; l < n; l++ {
+			x[l] += w[l]
+		}
+
+		if converged {
+			return res, nil
+		}
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+	}
+}
+
+func BiCGSTAB(a LinearOperator, f []float64, opts *SolveOptions) (*SolveResult, error) {
+	return BiCGSTABCtx(context.Background(), a, f, opts)
+}
+
+func BiCGSTABCtx(ctx context.Context, a LinearOperator, f []float64, opts *SolveOptions) (*SolveResult, error) {
+	if err := checkOperator(a, f); err != nil {
+		return nil, err
+	}
+	n := len(f)
+	o, err := opts.withDefaults(n)
+	if err != nil {
+		return nil, err
+	}
+	op, f, o := preconditionSide(a, f, o)
+	defer releasePrecondSide(op, f, o)
+
+	ws := o.Workspace
+	x, r, rHat := make([]float64, n), ws.vec(n), ws.vec(n)
+	p, pHat, v := ws.vec(n), ws.vec(n), ws.vec(n)
+	s, sHat, t := ws.vec(n), ws.vec(n), ws.vec(n)
+	defer releaseVecs(ws, r, rHat, p, pHat, v, s, sHat, t)
+	if o.X0 != nil {
+		copy(x, o.X0)
+	}
+	op.MulVec(v, x)
+	for i := 0; i < n; i++ {
+		r[i] = f[i] - v[i]
+		v[i] = 0
+	}
+	copy(rHat, r)
+
+	scale := o.residualScale(f)
+	r0 := VecNorm(r, EuclideanNorm) / scale
+	res := &SolveResult{X: x, Residual: r0, History: []float64{r0}}
+	if r0 <= o.Tol {
+		return res, nil
+	}
+
+	precond := func(dst, src []float64) {
+		if o.Precond != nil {
+			o.Precond.Apply(dst, src)
+		} else {
+			copy(dst, src)
+		}
+	}
+
+	rho, alpha, omega := 1., 1., 1.
+	for iter := 1; iter <= o.MaxIter; iter++ {
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+
+		rhoNext := dot(rHat, r)
+		if rhoNext == 0 {
+			return res, errors.New("bicgstab breakdown")
+		}
+		beta := rhoNext / rho * alpha / omega
+		rho = rhoNext
+		for i := 0; i < n; i++ {
+			p[i] = r[i] + beta * (p[i] - omega * v[i])
+		}
+		precond(pHat, p)
+		op.MulVec(v, pHat)
+		rv := dot(rHat, v)
+		if rv == 0 {
+			return res, errors.New("bicgstab breakdown")
+		}
+		alpha = rho / rv
+		for i := 0; i < n; i++ {
+			s[i] = r[i] - alpha * v[i]
+		}
+
+		res.Iterations = iter
+		if sNorm := VecNorm(s, EuclideanNorm) / scale; sNorm <= o.Tol {
+			for i := 0; i < n; i++ {
+				x[i] += alpha * pHat[i]
+			}
+			res.Residual = sNorm
+			res.History = append(res.History, sNorm)
+			return res, nil
+		}
+
+		precond(sHat, s)
+		op.MulVec(t, sHat)
+		tt := dot(t, t)
+		if tt == 0 {
+			return res, errors.New("bicgstab breakdown")
+		}
+		omega = dot(t, s) / tt
+		for i := 0; i < n; i++ {
+			x[i] += alpha * pHat[i] + omega * sHat[i]
+			r[i] = s[i] - omega * t[i]
+		}
+
+		rNorm := VecNorm(r, EuclideanNorm) / scale
+		res.Residual = rNorm
+		res.History = append(res.History, rNorm)
+		if diverged(rNorm, r0) {
+			return res, &ErrDiverged{iter, rNorm}
+		}
+		if rNorm <= o.Tol {
+			return res, nil
+		}
+		if omega == 0 {
+			return res, errors.New("bicgstab breakdown")
+		}
+	}
+
+	return res, &ErrNotConverged{o.MaxIter, res.Residual}
+}
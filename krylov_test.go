@@ -0,0 +1,154 @@
+package algnum
+
+import (
+	"context"
+	"testing"
+)
+
+// central differences for -u'' + p u' on a uniform grid, nonsymmetric and
+// not diagonally dominant for p > 1
+func convectionData(n int, p float64) [][]float64 {
+	data := init2dSlice(n, n)
+	for i := 0; i < n; i++ {
+		data[i][i] = 2
+		if i > 0 {
+			data[i][i - 1] = -1 - p
+		}
+		if i < n - 1 {
+			data[i][i + 1] = -1 + p
+		}
+	}
+	return data
+}
+
+// exact solve with a nearby matrix
+type luPreconditioner struct {
+	f *LUFactor
+}
+
+func (p luPreconditioner) Apply(dst, r []float64) {
+	_ = p.f.SolveTo(dst, r)
+}
+
+// convection matrix with small dense noise, preconditioned by the noise-free LU
+func perturbedConvection(n int, p float64) ([][]float64, Preconditioner) {
+	data := convectionData(n, p)
+	lu, _ := FactorizeLU(matOfRows(copy2dSlice(data), n, n))
+	noise := randData(n, n, -10, 10)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			data[i][j] += noise[i][j] / float64(100 * n)
+		}
+	}
+	return data, luPreconditioner{lu}
+}
+
+func TestGMRES(t *testing.T) {
+	n := 60
+	data, precond := perturbedConvection(n, 3)
+	a, _ := InitMat(data)
+	f := randFree(n, 1, 10)
+
+	lapack, err := lapackSolve(data, n, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	full, err := GMRES(a, f, n, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, full.X, 1e-6) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(full.X))
+	} else if full.Iterations > n || len(full.History) != full.Iterations + 1 {
+		t.Fatalf("result is wrong: %d iterations, history of %d", full.Iterations, len(full.History))
+	}
+	for i := 1; i < len(full.History); i++ {
+		if full.History[i] > full.History[i - 1] * (1 + 1e-12) {
+			t.Fatal("gmres residual history isn't monotone")
+		}
+	}
+
+	restarted, err := GMRES(a, f, 20, &SolveOptions{MaxIter: 5000})
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, restarted.X, 1e-6) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(restarted.X))
+	}
+
+	for _, side := range []int{LeftPrecond, RightPrecond} {
+		res, err := GMRES(a, f, 20, &SolveOptions{Precond: precond, PrecondSide: side})
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(lapack, res.X, 1e-6) {
+			t.Fatalf("result is wrong with side %d: expected\n %s,\ngot\n %s", side, VectToStr(lapack), VectToStr(res.X))
+		}
+		t.Logf("gmres(20) with lu preconditioner on side %d: %d iterations, without: %d", side, res.Iterations, restarted.Iterations)
+	}
+
+	k := 8
+	lapData := laplacian2dData(k)
+	fLap := randFree(k * k, 1, 10)
+	lapLapack, _ := lapackSolve(lapData, k * k, fLap)
+	lap, err := GMRES(laplacian2d{k}, fLap, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapLapack, lap.X, 1e-6) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapLapack), VectToStr(lap.X))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GMRESCtx(ctx, a, f, 20, nil); err != context.Canceled {
+		t.Fatalf("expected canceled error, got %v", err)
+	} else {
+		t.Log("gmres works correct")
+	}
+}
+
+func TestBiCGSTAB(t *testing.T) {
+	n := 60
+	data, precond := perturbedConvection(n, 1.5)
+	a, _ := InitMat(data)
+	f := randFree(n, 1, 10)
+
+	lapack, err := lapackSolve(data, n, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := BiCGSTAB(a, f, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, plain.X, 1e-6) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(plain.X))
+	} else if len(plain.History) != plain.Iterations + 1 {
+		t.Fatalf("result is wrong: %d iterations, history of %d", plain.Iterations, len(plain.History))
+	}
+
+	for _, side := range []int{LeftPrecond, RightPrecond} {
+		res, err := BiCGSTAB(a, f, &SolveOptions{Precond: precond, PrecondSide: side})
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(lapack, res.X, 1e-6) {
+			t.Fatalf("result is wrong with side %d: expected\n %s,\ngot\n %s", side, VectToStr(lapack), VectToStr(res.X))
+		}
+		t.Logf("bicgstab with lu preconditioner on side %d: %d iterations, without: %d", side, res.Iterations, plain.Iterations)
+	}
+
+	k := 8
+	lapData := laplacian2dData(k)
+	fLap := randFree(k * k, 1, 10)
+	lapLapack, _ := lapackSolve(lapData, k * k, fLap)
+	lap, err := BiCGSTAB(laplacian2d{k}, fLap, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapLapack, lap.X, 1e-6) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapLapack), VectToStr(lap.X))
+	}
+
+	if _, err := BiCGSTAB(a, f, &SolveOptions{MaxIter: 1}); err == nil {
+		t.Fatal("expected not converged error")
+	} else {
+		t.Log("bicgstab works correct")
+	}
+}
@@ -0,0 +1,148 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	LeastSquaresQR = iota
+	LeastSquaresNormal
+	LeastSquaresSVD
+)
+
+func LeastSquares(a *Matrix, b []float64) ([]float64, float64, int, error) {
+	return LeastSquaresBy(a, b, LeastSquaresQR)
+}
+
+// minimizes ||Ax - b||, returns x, ||Ax - b|| and the numerical rank of A;
+// normal equations square the condition number and need A of full column rank,
+// SVD gives the minimum norm solution for a rank deficient A
+func LeastSquaresBy(a *Matrix, b []float64, method int) ([]float64, float64, int, error) {
+	if a.rows != len(b) {
+		return nil, -1, 0, errors.New("matrix and free element dims don't match")
+	}
+	if a.rows == 0 {
+		return nil, -1, 0, errors.New("matrix or free element is empty")
+	}
+
+	var x []float64
+	var rank int
+	switch method {
+	case LeastSquaresNormal:
+		ata, atb := normalEquations(a, b)
+		f, err := FactorizeCholesky(ata)
+		if err != nil {
+			return nil, -1, 0, errors.New("normal equations aren't positive definite: matrix is rank deficient")
+		}
+		x, _ = f.Solve(atb)
+		rank = a.cols
+	case LeastSquaresSVD:
+		f, err := SVD(a, false)
+		if err != nil {
+			return nil, -1, 0, err
+		}
+		x, _ = f.Solve(b, 0)
+		rank = f.Rank(0)
+	default:
+		f, err := QR(a, true)
+		if err != nil {
+			return nil, -1, 0, err
+		}
+		x, err = f.Solve(b)
+		if err != nil {
+			return nil, -1, 0, err
+		}
+		rank = f.Rank()
+	}
+
+	return x, residualNorm(a, x, b), rank, nil
+}
+
+// minimizes sum w[i] * (A[i] * x - b[i])^2, the returned residual is weighted the same way
+func WeightedLeastSquares(a *Matrix, b, w []float64, method int) ([]float64, float64, int, error) {
+	if a.rows != len(b) || len(b) != len(w) {
+		return nil, -1, 0, errors.New("matrix, free element and weights dims don't match")
+	}
+	if a.rows == 0 || a.cols == 0 {
+		return nil, -1, 0, errors.New("matrix or free element is empty")
+	}
+
+	wa, _ := InitMatOfDims(a.rows, a.cols)
+	wb := make([]float64, len(b))
+	for i := 0; i < a.rows; i++ {
+		if w[i] < 0 {
+			return nil, -1, 0, errors.New("weights must be non-negative")
+		}
+		sw := math.Sqrt(w[i])
+		for j := 0; j < a.cols; j++ {
+			wa.data[i * wa.stride + j] = sw * a.data[i * a.stride + j]
+		}
+		wb[i] = sw * b[i]
+	}
+
+	return LeastSquaresBy(wa, wb, method)
+}
+
+// minimizes ||Ax - b||^2 + lambda^2 * ||x||^2 by solving the stacked system
+// [A; lambda * E] x = [b; 0], the returned residual is ||Ax - b||
+func TikhonovLeastSquares(a *Matrix, b []float64, lambda float64, method int) ([]float64, float64, int, error) {
+	if a.rows != len(b) {
+		return nil, -1, 0, errors.New("matrix and free element dims don't match")
+	}
+	if a.rows == 0 || a.cols == 0 {
+		return nil, -1, 0, errors.New("matrix or free element is empty")
+	}
+
+	aug, _ := InitMatOfDims(a.rows + a.cols, a.cols)
+	for i := 0; i < a.rows; i++ {
+		copy(aug.row(i), a.row(i))
+	}
+	for j := 0; j < a.cols; j++ {
+		aug.data[(a.rows + j) * aug.stride + j] = lambda
+	}
+	augB := make([]float64, a.rows + a.cols)
+	copy(augB, b)
+
+	x, _, rank, err := LeastSquaresBy(aug, augB, method)
+	if err != nil {
+		return nil, -1, 0, err
+	}
+
+	return x, residualNorm(a, x, b), rank, nil
+}
+
+func normalEquations(a *Matrix, b []float64) (*Matrix, []float64) {
+	ata, _ := InitMatOfDim(a.cols)
+	atb := make([]float64, a.cols)
+	for i := 0; i < a.rows; i++ {
+		ai := a.row(i)
+		for j := 0; j < a.cols; j++ {
+			if ai[j] == 0 {
+				continue
+			}
+			for k := j; k < a.cols; k++ {
+				ata.data[j * ata.stride + k] += ai[j] * ai[k]
+			}
+			atb[j] += ai[j] * b[i]
+		}
+	}
+	for j := 0; j < a.cols; j++ {
+		for k := 0; k < j; k++ {
+			ata.data[j * ata.stride + k] = ata.data[k * ata.stride + j]
+		}
+	}
+	return ata, atb
+}
+
+func residualNorm(a *Matrix, x, b []float64) float64 {
+	var norm float64
+	for i := 0; i < a.rows; i++ {
+		r := -b[i]
+		for j := 0; j < a.cols; j++ {
+			r += a.data[i * a.stride + j] * x[j]
+		}
+		norm += r * r
+	}
+	return math.Sqrt(norm)
+}
@@ -0,0 +1,134 @@
+package algnum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLeastSquares(t *testing.T) {
+	data := [][]float64{
+		{1, 1},
+		{1, 2},
+		{1, 3},
+		{1, 4},
+	}
+	a, _ := InitMat(data)
+	b := []float64{6, 5, 7, 10}
+
+	expectedRes := []float64{3.5, 1.4}
+	expectedResid := math.Sqrt(4.2)
+
+	for _, method := range []int{LeastSquaresQR, LeastSquaresNormal, LeastSquaresSVD} {
+		res, resid, rank, err := LeastSquaresBy(a, b, method)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(res, expectedRes, Epsilon) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes), VectToStr(res))
+		} else if math.Abs(resid - expectedResid) > Epsilon || rank != 2 {
+			t.Fatalf("residual or rank is wrong: expected %0.15f and %d, got %0.15f and %d", expectedResid, 2, resid, rank)
+		}
+	}
+
+	defData := [][]float64{
+		{1, 2, 3},
+		{1, 2, 3},
+		{2, 4, 6},
+		{1, 1, 1},
+	}
+	def, _ := InitMat(defData)
+	defB := []float64{6, 6, 12, 3}
+
+	res, resid, rank, err := LeastSquares(def, defB)
+	if err != nil {
+		t.Fatal(err)
+	} else if rank != 2 || resid > 1e-9 {
+		t.Fatalf("rank or residual is wrong: expected %d and 0, got %d and %0.15f", 2, rank, resid)
+	}
+	t.Log("rank deficient basic solution:", VectToStr(res))
+
+	// minimum norm solution of x + 2y + 3z = 6, x + y + z = 3
+	minNorm, _, rank, err := LeastSquaresBy(def, defB, LeastSquaresSVD)
+	expectedMinNorm := []float64{1, 1, 1}
+	if err != nil {
+		t.Fatal(err)
+	} else if rank != 2 || !VectsEq(minNorm, expectedMinNorm, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedMinNorm), VectToStr(minNorm))
+	}
+
+	if _, _, _, err := LeastSquaresBy(def, defB, LeastSquaresNormal); err == nil {
+		t.Fatal("expected rank deficiency error")
+	} else {
+		t.Log("least squares works correct")
+	}
+}
+
+func TestWeightedLeastSquares(t *testing.T) {
+	data := [][]float64{
+		{1, 1},
+		{1, 2},
+		{1, 3},
+		{1, 4},
+	}
+	a, _ := InitMat(data)
+	b := []float64{6, 5, 7, 100}
+	w := []float64{1, 1, 1, 0}
+
+	// zero weight drops the outlier
+	res, _, _, err := WeightedLeastSquares(a, b, w, LeastSquaresQR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRes := []float64{5, 0.5}
+	if !VectsEq(res, expectedRes, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes), VectToStr(res))
+	}
+
+	if _, _, _, err := WeightedLeastSquares(&Matrix{}, nil, nil, LeastSquaresQR); err == nil {
+		t.Fatal("expected empty matrix error")
+	}
+	if _, _, _, err := WeightedLeastSquares(&Matrix{rows: 3}, b[ : 3], w[ : 3], LeastSquaresQR); err == nil {
+		t.Fatal("expected empty matrix error for zero columns")
+	}
+	if _, _, _, err := WeightedLeastSquares(a, b, w[ : 3], LeastSquaresQR); err == nil {
+		t.Fatal("expected dims error")
+	} else {
+		t.Log("weighted least squares works correct")
+	}
+}
+
+func TestTikhonovLeastSquares(t *testing.T) {
+	data := [][]float64{
+		{1, 1},
+		{1, 2},
+		{1, 3},
+		{1, 4},
+	}
+	a, _ := InitMat(data)
+	b := []float64{6, 5, 7, 10}
+	lambda := 0.5
+
+	// (A^T A + lambda^2 E) x = A^T b
+	ata, atb := normalEquations(a, b)
+	for i := 0; i < 2; i++ {
+		ata.data[i * ata.stride + i] += lambda * lambda
+	}
+	expectedRes, err := Gauss(ata, atb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, method := range []int{LeastSquaresQR, LeastSquaresNormal, LeastSquaresSVD} {
+		res, resid, _, err := TikhonovLeastSquares(a, b, lambda, method)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(res, expectedRes, Epsilon) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedRes), VectToStr(res))
+		} else if math.Abs(resid - residualNorm(a, res, b)) > Epsilon {
+			t.Fatalf("residual is wrong: got %0.15f", resid)
+		}
+	}
+	if _, _, _, err := TikhonovLeastSquares(&Matrix{rows: 3}, b[ : 3], lambda, LeastSquaresQR); err == nil {
+		t.Fatal("expected empty matrix error for zero columns")
+	}
+	t.Log("tikhonov least squares works correct")
+}
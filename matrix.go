@@ -1,21 +1,30 @@
 package algnum
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
-	"sync"
 )
 
 const (
 	EuclideanNorm = iota
 	InfinityNorm
+	OneNorm
 	Epsilon = 1e-6
 )
 
+const (
+	invParMin   = 64
+	invParChunk = 16
+)
+
+// row-major storage: element (i, j) is data[i * stride + j], the stride
+// may exceed cols when the matrix is a view into a larger one
 type Matrix struct {
-	data [][]float64
+	data       []float64
 	rows, cols int
+	stride     int
 }
 
 func (mat *Matrix) Rows() int {
@@ -26,10 +35,71 @@ func (mat *Matrix) Cols() int {
 	return mat.cols
 }
 
+func (mat *Matrix) Dims() (int, int) {
+	return mat.rows, mat.cols
+}
+
 func (mat *Matrix) IsSquare() bool {
 	return mat.rows == mat.cols
 }
 
+func newMat(rows, cols int) *Matrix {
+	return &Matrix{make([]float64, rows * cols), rows, cols, cols}
+}
+
+// wraps the rows without copying when they are consecutive parts of one
+// backing array, as init2dSlice and copy2dSlice make them, and copies
+// them otherwise
+func matOfRows(data [][]float64, rows, cols int) *Matrix {
+	if rows == 0 || cols == 0 {
+		return &Matrix{rows: rows, cols: cols, stride: cols}
+	}
+	if backing := data[0][ : cap(data[0])]; len(backing) >= rows * cols {
+		contiguous := true
+		for i := 0; i < rows && contiguous; i++ {
+			contiguous = len(data[i]) == cols && &data[i][0] == &backing[i * cols]
+		}
+		if contiguous {
+			return &Matrix{backing[ : rows * cols : rows * cols], rows, cols, cols}
+		}
+	}
+
+	res := newMat(rows, cols)
+	for i := 0; i < rows; i++ {
+		copy(res.row(i), data[i])
+	}
+	return res
+}
+
+// the returned slice shares the storage of m
+func (m *Matrix) row(i int) []float64 {
+	return m.data[i * m.stride : i * m.stride + m.cols : i * m.stride + m.cols]
+}
+
+// the view shares the storage of m
+func (m *Matrix) view(i, j, rows, cols int) *Matrix {
+	v := m.viewOf(i, j, rows, cols)
+	return &v
+}
+
+func (m *Matrix) viewOf(i, j, rows, cols int) Matrix {
+	if rows == 0 || cols == 0 {
+		return Matrix{rows: rows, cols: cols, stride: m.stride}
+	}
+	start := i * m.stride + j
+	return Matrix{m.data[start : start + (rows - 1) * m.stride + cols], rows, cols, m.stride}
+}
+
+// row headers into the storage of m for the algorithms working on
+// [][]float64: writing the elements changes m, swapping the headers doesn't
+func (m *Matrix) rowSlices() [][]float64 {
+	res := make([][]float64, m.rows)
+	for i := range res {
+		res[i] = m.row(i)
+	}
+	return res
+}
+
 func InitMat(data [][]float64) (*Matrix, error) {
 	if len(data) == 0 {
 		return &Matrix{}, nil
@@ -43,7 +113,11 @@ func InitMat(data [][]float64) (*Matrix, error) {
 		}
 	}
 
-	return &Matrix{data: copy2dSlice(data), rows: rows, cols: cols}, nil
+	res := newMat(rows, cols)
+	for i, d := range data {
+		copy(res.row(i), d)
+	}
+	return res, nil
 }
 
 func InitMatOfDim(dim int) (*Matrix, error) {
@@ -54,9 +128,7 @@ func InitMatOfDim(dim int) (*Matrix, error) {
 		return &Matrix{}, nil
 	}
 
-	data := init2dSlice(dim, dim)
-
-	return &Matrix{data: data, rows: len(data), cols: len(data[0])}, nil
+	return newMat(dim, dim), nil
 }
 
 func InitMatOfDims(rows, cols int) (*Matrix, error) {
@@ -71,9 +143,7 @@ func InitMatOfDims(rows, cols int) (*Matrix, error) {
 		}
 	}
 
-	data := init2dSlice(rows, cols)
-
-	return &Matrix{data: data, rows: rows, cols: cols}, nil
+	return newMat(rows, cols), nil
 }
 
 func IdentityMat(dim int) (*Matrix, error) {
@@ -84,12 +154,12 @@ func IdentityMat(dim int) (*Matrix, error) {
 		return &Matrix{}, nil
 	}
 
-	data := init2dSlice(dim, dim)
+	res := newMat(dim, dim)
 	for i := 0; i < dim; i++ {
-		data[i][i] = 1
+		res.data[i * res.stride + i] = 1
 	}
 
-	return &Matrix{data: data, rows: len(data), cols: len(data[0])}, nil
+	return res, nil
 }
 
 func (m *Matrix) IsDiagDominant() bool {
@@ -97,13 +167,13 @@ func (m *Matrix) IsDiagDominant() bool {
 		return false
 	}
 	for i := 0; i < m.rows; i++ {
-		aii := math.Abs(m.data[i][i])
+		aii := math.Abs(m.data[i * m.stride + i])
 		var sum float64
 		for j := 0; j < m.cols; j++ {
 			if j == i {
 				continue
 			}
-			sum += math.Abs(m.data[i][j])
+			sum += math.Abs(m.data[i * m.stride + j])
 			if aii < sum {
 				return false
 			}
@@ -118,7 +188,7 @@ func (m *Matrix) IsSymmetric() bool {
 	}
 	for i := 0; i < m.rows; i++ {
 		for j := 0; j <= i; j++ {
-			if m.data[i][j] != m.data[j][i] {
+			if m.data[i * m.stride + j] != m.data[j * m.stride + i] {
 				return false
 			}
 		}
@@ -131,103 +201,107 @@ func (m *Matrix) Det() (float64, error) {
 		return -1, errors.New("matrix isn't square")
 	}
 
-	return detRec(m.data), nil
-}
-
-func del(data [][]float64, row, col int) [][]float64 {
-	res := init2dSlice(len(data) - 1, len(data[0]) - 1)
-	var k, l int
-	for i := range data {
-		if i == row {
-			continue
-		}
-		l = 0
-		for j := range data[i] {
-			if j == col {
-				continue
-			}
-			res[k][l] = data[i][j]
-			l++
-		}
-		k++
-	}
-	return res
-}
-
-func detRec(data [][]float64) float64 {
-	dim := len(data)
-	if dim == 1 {
-		return data[0][0]
-	}
-	if dim == 2 {
-		return data[0][0] * data[1][1] - data[0][1] * data[1][0]
-	}
+	lu, _, sign := pivotedLU(m.rowSlices())
 
-	res := float64(0)
-	sign := float64(1)
-	for i := 0; i < dim; i++ {
-		res += sign * data[i][0] * detRec(del(data, i, 0))
-		sign *= -1
+	res := sign
+	for i := range lu {
+		res *= lu[i][i]
 	}
 
-	return res
+	return res, nil
 }
 
-func adjoint(data [][]float64) [][]float64 {
-	if len(data) == 1 {
-		return [][]float64{{data[0][0]}}
+func (m *Matrix) LogDet() (float64, float64, error) {
+	if !m.IsSquare() {
+		return -1, 0, errors.New("matrix isn't square")
 	}
 
-	res := init2dSlice(len(data), len(data[0]))
-	sign := float64(-1)
+	lu, _, sign := pivotedLU(m.rowSlices())
 
-	var wg sync.WaitGroup
-	for i := range data {
-		for j := range data[i] {
-			wg.Add(1)
-			go func(res [][]float64, i, j int, sign *float64) {
-				defer wg.Done()
-				cofactor := del(data, i, j)
-				if (i + j) % 2 == 0 {
-					*sign = 1
-				} else {
-					*sign = -1
-				}
-
-				res[j][i] = *sign * detRec(cofactor)
-			}(res, i, j, &sign)
+	var logAbs float64
+	for i := range lu {
+		uii := lu[i][i]
+		if uii == 0 {
+			return math.Inf(-1), 0, nil
+		}
+		if uii < 0 {
+			sign = -sign
 		}
+		logAbs += math.Log(math.Abs(uii))
 	}
 
-	wg.Wait()
-	return res
+	return logAbs, sign, nil
 }
 
 func (m *Matrix) Inverse() (*Matrix, error) {
-	det, err := m.Det()
+	return m.InverseCtx(context.Background())
+}
+
+// the columns are solved on the default executor, the tasks stop once
+// ctx is done
+func (m *Matrix) InverseCtx(ctx context.Context) (*Matrix, error) {
+	if !m.IsSquare() {
+		return nil, errors.New("matrix isn't square")
+	}
+	n := m.rows
+	if n == 0 {
+		return nil, errors.New("matrix is empty")
+	}
+
+	// the factor and the scratch vectors come from a pooled workspace
+	ws := workspacePool.Get().(*Workspace)
+	defer workspacePool.Put(ws)
+	f, err := FactorizeLUWithWorkspace(m, ws)
 	if err != nil {
 		return nil, err
 	}
-	if math.Abs(det) <= Epsilon {
-		return nil, errors.New("matrix is singular")
-	}
+	defer f.Release()
 
-	invData := init2dSlice(m.rows, m.cols)
+	inv := newMat(n, n)
 
-	adj := adjoint(m.data)
-	var wg sync.WaitGroup
-	for i := range m.data {
-		for j := range m.data[i] {
-			wg.Add(1)
-			go func(invData [][]float64, i, j int) {
-				defer wg.Done()
-				invData[i][j] = adj[i][j] / det
-			}(invData, i, j)
+	// every task solves a chunk of columns with its own scratch vectors,
+	// small matrices aren't worth the goroutines
+	chunk := invParChunk
+	if n < invParMin {
+		chunk = n
+	}
+	DefaultExecutor().ParallelFor((n + chunk - 1) / chunk, func(c int) {
+		e, x := ws.vec(n), ws.vec(n)
+		defer releaseVecs(ws, e, x)
+		for i := range e {
+			e[i] = 0
+		}
+		for j := c * chunk; j < intMin((c + 1) * chunk, n); j++ {
+			if ctx.Err() != nil {
+				return
+			}
+			e[j] = 1
+			pivotedLUSolve(f.lu, f.perm, e, x)
+			e[j] = 0
+			for i := 0; i < n; i++ {
+				inv.data[i * inv.stride + j] = x[i]
+			}
+		}
+	})
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// reciprocal condition number in the 1-norm
+	rcond := 1 / (m.Norm(OneNorm) * inv.Norm(OneNorm))
+	if math.IsNaN(rcond) || rcond < machEps {
+		// the pivots looked regular, so the rank comes from the singular values
+		rank, err := m.Rank(0)
+		if err != nil {
+			return nil, err
+		}
+		cond := math.Inf(1)
+		if !math.IsNaN(rcond) {
+			cond = 1 / rcond
 		}
+		return nil, &SingularMatrixError{Rank: rank, Cond: cond}
 	}
 
-	wg.Wait()
-	inv := &Matrix{invData, m.rows, m.cols}
 	return inv, nil
 }
 
@@ -239,17 +313,28 @@ func (m *Matrix) Norm(normType int) float64 {
 		for i := 0; i < m.rows; i++ {
 			var absRowSum float64
 			for j := 0; j < m.cols; j++ {
-				absRowSum += math.Abs(m.data[i][j])
+				absRowSum += math.Abs(m.data[i * m.stride + j])
 			}
 			if absRowSum >= norm {
 				norm = absRowSum
 			}
 		}
 		return norm
+	case OneNorm:
+		for j := 0; j < m.cols; j++ {
+			var absColSum float64
+			for i := 0; i < m.rows; i++ {
+				absColSum += math.Abs(m.data[i * m.stride + j])
+			}
+			if absColSum >= norm {
+				norm = absColSum
+			}
+		}
+		return norm
 	default:
 		for i := 0; i < m.rows; i++ {
 			for j := 0; j < m.cols; j++ {
-				norm += m.data[i][j] * m.data[i][j]
+				norm += m.data[i * m.stride + j] * m.data[i * m.stride + j]
 			}
 		}
 		return math.Sqrt(norm)
@@ -270,65 +355,134 @@ func VectsEq(a, b []float64, eps float64) bool {
 	return true
 }
 
+// copy of rows i : n and columns j : k of m
 func MatsSlice(m *Matrix, i, n, j, k int) (*Matrix, error) {
-	if i < 0 || j < 0 || n > m.rows || k > m.cols {
+	if i < 0 || j < 0 || n > m.rows || k > m.cols || i > n || j > k {
 		return nil, errors.New("indexes are out of bounds")
 	}
 
-	return &Matrix{copy2dSlice(m.data[i : n][j : k]), n - i, k - j}, nil
+	return m.view(i, j, n - i, k - j).Clone(), nil
 }
 
-func MatsSum(a, b *Matrix) (*Matrix, error) {
-	if a.rows != b.rows || a.cols != b.cols {
-		return nil, errors.New("matrixs dims don't match")
+// the view aliases m, writing to it changes m
+func (m *Matrix) View(r0, c0, rows, cols int) (*Matrix, error) {
+	if r0 < 0 || c0 < 0 || rows < 0 || cols < 0 || r0 + rows > m.rows || c0 + cols > m.cols {
+		return nil, errors.New("indexes are out of bounds")
 	}
 
-	resData := copy2dSlice(a.data)
-	fmt.Println(resData)
-	for i := range resData {
-		for j := range resData[i] {
-			resData[i][j] += b.data[i][j]
-		}
+	return m.view(r0, c0, rows, cols), nil
+}
+
+// the row aliases m
+func (m *Matrix) Row(i int) ([]float64, error) {
+	if i < 0 || i >= m.rows {
+		return nil, errors.New("index is out of bounds")
 	}
-	res := &Matrix{resData, a.rows, a.cols}
 
+	return m.row(i), nil
+}
+
+// columns aren't contiguous, so unlike Row it's a copy
+func (m *Matrix) Col(j int) ([]float64, error) {
+	if j < 0 || j >= m.cols {
+		return nil, errors.New("index is out of bounds")
+	}
+
+	res := make([]float64, m.rows)
+	for i := range res {
+		res[i] = m.data[i * m.stride + j]
+	}
 	return res, nil
 }
 
-func MatsSub(a, b *Matrix) (*Matrix, error) {
+func (m *Matrix) Diag() []float64 {
+	res := make([]float64, intMin(m.rows, m.cols))
+	for i := range res {
+		res[i] = m.data[i * m.stride + i]
+	}
+	return res
+}
+
+// partitions m into p block rows and q block columns of views, the sizes
+// of the blocks differ by at most one with the larger ones first
+func (m *Matrix) Block(p, q int) ([][]*Matrix, error) {
+	if p <= 0 || q <= 0 || p > m.rows || q > m.cols {
+		return nil, errors.New("wrong number of blocks")
+	}
+
+	res := make([][]*Matrix, p)
+	r0 := 0
+	for bi := 0; bi < p; bi++ {
+		rows := m.rows / p
+		if bi < m.rows % p {
+			rows++
+		}
+		res[bi] = make([]*Matrix, q)
+		c0 := 0
+		for bj := 0; bj < q; bj++ {
+			cols := m.cols / q
+			if bj < m.cols % q {
+				cols++
+			}
+			res[bi][bj] = m.view(r0, c0, rows, cols)
+			c0 += cols
+		}
+		r0 += rows
+	}
+	return res, nil
+}
+
+// the clone has its own contiguous storage even if m is a view
+func (m *Matrix) Clone() *Matrix {
+	res := newMat(m.rows, m.cols)
+	for i := 0; i < m.rows; i++ {
+		copy(res.row(i), m.row(i))
+	}
+	return res
+}
+
+// src mustn't be a view overlapping m in different rows
+func (m *Matrix) CopyFrom(src *Matrix) error {
+	if m.rows != src.rows || m.cols != src.cols {
+		return errors.New("matrixs dims don't match")
+	}
+
+	for i := 0; i < m.rows; i++ {
+		copy(m.row(i), src.row(i))
+	}
+	return nil
+}
+
+func MatsSum(a, b *Matrix) (*Matrix, error) {
 	if a.rows != b.rows || a.cols != b.cols {
 		return nil, errors.New("matrixs dims don't match")
 	}
 
-	resData := copy2dSlice(a.data)
-	for i := range resData {
-		for j := range resData[i] {
-			resData[i][j] -= b.data[i][j]
+	res := a.Clone()
+	for i := 0; i < a.rows; i++ {
+		ri, bi := res.row(i), b.row(i)
+		for j := range ri {
+			ri[j] += bi[j]
 		}
 	}
-	res := &Matrix{resData, a.rows, a.cols}
 
 	return res, nil
 }
 
-func slicesSum(a, b [][]float64) [][]float64 {
-	res := copy2dSlice(a)
-	for i := range res {
-		for j := range res[i] {
-			res[i][j] += b[i][j]
-		}
+func MatsSub(a, b *Matrix) (*Matrix, error) {
+	if a.rows != b.rows || a.cols != b.cols {
+		return nil, errors.New("matrixs dims don't match")
 	}
-	return res
-}
 
-func slicesSub(a, b [][]float64) [][]float64 {
-	res := copy2dSlice(a)
-	for i := range res {
-		for j := range res[i] {
-			res[i][j] -= b[i][j]
+	res := a.Clone()
+	for i := 0; i < a.rows; i++ {
+		ri, bi := res.row(i), b.row(i)
+		for j := range ri {
+			ri[j] -= bi[j]
 		}
 	}
-	return res
+
+	return res, nil
 }
 
 func MatsEq(a, b *Matrix, eps float64) bool {
@@ -336,8 +490,8 @@ func MatsEq(a, b *Matrix, eps float64) bool {
 		return false
 	}
 
-	for i, ai := range a.data {
-		if !VectsEq(ai, b.data[i], eps) {
+	for i := 0; i < a.rows; i++ {
+		if !VectsEq(a.row(i), b.row(i), eps) {
 			return false
 		}
 	}
@@ -362,8 +516,8 @@ func VectToStr(a []float64) string {
 func (mat *Matrix) ToStr() string {
 	res := "["
 
-	for _, m := range mat.data {
-		res += VectToStr(m) + ",\n"
+	for i := 0; i < mat.rows; i++ {
+		res += VectToStr(mat.row(i)) + ",\n"
 	}
 
 	if len(res) > 1 {
@@ -387,6 +541,11 @@ func VecNorm(x []float64, normType int) float64 {
 			}
 		}
 		return norm
+	case OneNorm:
+		for _, xi := range x {
+			norm += math.Abs(xi)
+		}
+		return norm
 	default:
 		for _, xi := range x {
 			norm += xi * xi
@@ -487,12 +646,15 @@ func SwitchVecEls(a []float64, i, j int) ([]float64, error) {
 }
 
 func MatConstMul(a *Matrix, c float64) *Matrix {
-	resData := make([][]float64, a.rows)
-	for i, ai := range a.data {
-		resData[i] = VecConstMul(ai, c)
+	res := newMat(a.rows, a.cols)
+	for i := 0; i < a.rows; i++ {
+		ri := res.row(i)
+		for j, aij := range a.row(i) {
+			ri[j] = aij * c
+		}
 	}
 
-	return &Matrix{data: resData, rows: a.rows, cols: a.cols}
+	return res
 }
 
 func MatVecMul(a *Matrix, x []float64) ([]float64, error) {
@@ -505,20 +667,41 @@ func MatVecMul(a *Matrix, x []float64) ([]float64, error) {
 	}
 
 	res := make([]float64, len(x))
-	for i, ai := range a.data {
-		scalProd, _ := ScalarProd(ai, x)
+	for i := 0; i < a.rows; i++ {
+		scalProd, _ := ScalarProd(a.row(i), x)
 		res[i] = scalProd
 	}
 
 	return res, nil
 }
 
+func (m *Matrix) At(i, j int) float64 {
+	return m.data[i * m.stride + j]
+}
+
+// dst = m * x, dst must not alias x
+func (m *Matrix) MulVec(dst, x []float64) {
+	matVecTo(m, x, dst)
+}
+
+// dst = mᵀ * x, dst must not alias x
+func (m *Matrix) MulVecTrans(dst, x []float64) {
+	for j := 0; j < m.cols; j++ {
+		dst[j] = 0
+	}
+	for i := 0; i < m.rows; i++ {
+		for j, mij := range m.row(i) {
+			dst[j] += mij * x[i]
+		}
+	}
+}
+
 func TransposeMat(mat *Matrix) *Matrix {
-	transMat, _ := InitMatOfDims(mat.rows, mat.cols)
+	transMat := newMat(mat.cols, mat.rows)
 
-	for i := range mat.data {
-		for j := range mat.data {
-			transMat.data[j][i] = mat.data[i][j]
+	for i := 0; i < mat.rows; i++ {
+		for j, mij := range mat.row(i) {
+			transMat.data[j * transMat.stride + i] = mij
 		}
 	}
 
@@ -530,16 +713,11 @@ func MatMul(a, b *Matrix) (*Matrix, error) {
 		return nil, errors.New("matrix dims don't match")
 	}
 
-	transRes, _ := InitMatOfDims(b.rows, b.cols)
-
-	transB := TransposeMat(b)
-	for i, bi := range transB.data {
-		row, _ := MatVecMul(a, bi)
-		transRes.data[i] = row
+	res := newMat(a.rows, b.cols)
+	if err := Mul(res, a, b); err != nil {
+		return nil, err
 	}
 
-	res := TransposeMat(transRes)
-
 	return res, nil
 }
 
@@ -548,11 +726,15 @@ func SwitchRows(mat *Matrix, i, j int) (*Matrix, error) {
 		return nil, errors.New("wrong indexes")
 	}
 
-	resData := copy2dSlice(mat.data)
+	res := mat.Clone()
 
-	resData[i], resData[j] = resData[j], resData[i]
+	// rows can't be swapped by their headers in the flat storage
+	ri, rj := res.row(i), res.row(j)
+	for k := range ri {
+		ri[k], rj[k] = rj[k], ri[k]
+	}
 
-	return &Matrix{data: resData, rows: mat.rows, cols: mat.cols}, nil
+	return res, nil
 }
 
 func SwitchCols(mat *Matrix, i, j int) (*Matrix, error) {
@@ -560,13 +742,14 @@ func SwitchCols(mat *Matrix, i, j int) (*Matrix, error) {
 		return nil, errors.New("wrong indexes")
 	}
 
-	resData := copy2dSlice(mat.data)
+	res := mat.Clone()
 
-	for k := range resData {
-		resData[k][i], resData[k][j] = resData[k][j], resData[k][i]
+	for k := 0; k < res.rows; k++ {
+		rk := res.row(k)
+		rk[i], rk[j] = rk[j], rk[i]
 	}
 
-	return &Matrix{data: resData, rows: mat.rows, cols: mat.cols}, nil
+	return res, nil
 }
 
 func LU(m *Matrix) (*Matrix, *Matrix, error) {
@@ -584,101 +767,31 @@ func LU(m *Matrix) (*Matrix, *Matrix, error) {
 				for k := 0; k <= i - 1; k++ {
 					sum += lData[i][k] * uData[k][j]
 				}
-				uData[i][j] = m.data[i][j] - sum
+				uData[i][j] = m.data[i * m.stride + j] - sum
 			} else {
 				var sum float64
 				for k := 0; k <= j - 1; k++ {
 					sum += lData[i][k] * uData[k][j]
 				}
-				lData[i][j] = (m.data[i][j] - sum) / uData[j][j]
+				lData[i][j] = (m.data[i * m.stride + j] - sum) / uData[j][j]
 			}
 		}
 	}
 
-	return &Matrix{lData, n, n}, &Matrix{uData, n, n}, nil
+	return matOfRows(lData, n, n), matOfRows(uData, n, n), nil
 }
 
 func (m *Matrix) DetLU() (float64, error) {
-	if !m.IsSquare() {
-		return -1, errors.New("matrix isn't square")
-	}
-
-	n := m.rows
-	_, u, err := LU(m)
-	if err != nil {
-		return -1, err
-	}
-
-	res := float64(1)
-	for i := 0; i < n; i++ {
-		res *= u.data[i][i]
-	}
-
-	return res, nil
+	return m.Det()
 }
 
+// goes through the pivoted factorization, plain LU breaks down on a zero
+// pivot even when the matrix is regular
 func (m *Matrix) InverseLU() (*Matrix, error) {
-	if !m.IsSquare() {
-		return nil, errors.New("matrix isn't square")
-	}
-
-	d, err := m.DetLU()
-	if err != nil {
-		return nil, err
-	}
-	if math.Abs(d) <= Epsilon || math.IsNaN(d) {
-		return nil, errors.New("matrix is singular")
-	}
-
-	n := m.rows
-
-	l, u, err := LU(m)
+	p, l, u, _, err := PLU(m)
 	if err != nil {
 		return nil, err
 	}
 
-	resData := init2dSlice(n, n)
-	// ***Using Gauss***
-	//for i := 0; i < n; i++ {
-	//	resData[i][i] = 1
-	//}
-	//
-	//for i := 0; i < n; i++ {
-	//	f := make([]float64, n)
-	//	f[i] = 1
-	//
-	//	resI, _ := LUSolve(l, u, f)
-	//	resData[i] = resI
-	//}
-	//
-	//resTrans := &Matrix{resData, n, n}
-	//
-	//return TransposeMat(resTrans), nil
-
-	// ***Iterative***
-	for i := n - 1; i >= 0; i-- {
-		for j := n - 1; j >= 0; j-- {
-			if i < j {
-				var sum float64
-				for k := i + 1; k < n; k++ {
-					sum += u.data[i][k] * resData[k][j]
-				}
-				resData[i][j] = -sum / u.data[i][i]
-			} else if i == j {
-				var sum float64
-				for k := j + 1; k < n; k++ {
-					sum += u.data[j][k] * resData[k][j]
-				}
-				resData[j][i] = (1 - sum) / u.data[j][j]
-			} else {
-				var sum float64
-				for k := j + 1; k < n; k++ {
-					sum += resData[i][k] * l.data[k][j]
-				}
-				resData[i][j] = -sum
-			}
-		}
-	}
-
-	return &Matrix{resData, n, n}, nil
+	return InversePLU(p, l, u)
 }
\ No newline at end of file
@@ -1,7 +1,10 @@
 package algnum
 
 import (
+	"context"
+	"errors"
 	"gonum.org/v1/gonum/mat"
+	"math"
 	"testing"
 )
 
@@ -162,7 +165,7 @@ func TestMatrix_Det(t *testing.T) {
 	res, err := mat.Det()
 	if err != nil {
 		t.Fatal(err)
-	} else if res != expectedRes {
+	} else if math.Abs(res - expectedRes) > Epsilon {
 		t.Fatalf("result is wrong: expected %0.15f, got %0.15f", expectedRes, res)
 	} else {
 		t.Log("det works correct")
@@ -286,4 +289,326 @@ func TestMatrix_InverseLU(t *testing.T) {
 	t.Logf("LU inv vs. LAPACK norm: %0.15f; LU inv vs. my inv: %0.15f",
 		subLAPACK.Norm(EuclideanNorm), subMy.Norm(EuclideanNorm),
 	)
-}
\ No newline at end of file
+	// zero leading pivot, unpivoted LU breaks down here
+	perm, _ := InitMat([][]float64{{0, 1, 0}, {0, 0, 1}, {1, 0, 0}})
+	permInv, err := perm.InverseLU()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := TransposeMat(perm); !MatsEq(expected, permInv, 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", expected.ToStr(), permInv.ToStr())
+	}
+
+	singular, _ := InitMat([][]float64{{0, 1}, {0, 2}})
+	if _, err := singular.InverseLU(); err == nil {
+		t.Fatal("inverse of singular matrix doesn't fail")
+	} else {
+		t.Log("inverse LU of permutation matrix works correct")
+	}
+}
+
+func TestMatrix_DetPivot(t *testing.T) {
+	data := [][]float64{
+		{0, 1},
+		{1, 0},
+	}
+	mat, _ := InitMat(data)
+
+	d, err := mat.DetLU()
+	if err != nil {
+		t.Fatal(err)
+	} else if d != -1 {
+		t.Fatalf("result is wrong: expected %0.15f, got %0.15f", -1.0, d)
+	}
+
+	dataN := randData(50, 50, 1, 10)
+	matN, _ := InitMat(dataN)
+
+	dN, err := matN.Det()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lapack := lapackDet(dataN, len(dataN))
+	if math.Abs(dN - lapack) > 1e-8 * math.Abs(lapack) {
+		t.Fatalf("result is wrong: expected %e, got %e", lapack, dN)
+	} else {
+		t.Logf("det works correct: %e vs. LAPACK %e", dN, lapack)
+	}
+}
+
+func TestMatrix_LogDet(t *testing.T) {
+	data := [][]float64{
+		{3, 2, 1},
+		{1, 3, 2},
+		{1, 2, 4},
+	}
+	mat, _ := InitMat(data)
+
+	logAbs, sign, err := mat.LogDet()
+	if err != nil {
+		t.Fatal(err)
+	} else if sign != 1 || math.Abs(logAbs - math.Log(19)) > Epsilon {
+		t.Fatalf("result is wrong: expected (%0.15f, 1), got (%0.15f, %0.0f)", math.Log(19), logAbs, sign)
+	}
+
+	big, _ := IdentityMat(400)
+	for i := 0; i < 400; i++ {
+		big.data[i * big.stride + i] = -1e3
+	}
+	logAbs, sign, err = big.LogDet()
+	if err != nil {
+		t.Fatal(err)
+	} else if sign != 1 || math.Abs(logAbs - 400 * math.Log(1e3)) > Epsilon {
+		t.Fatalf("result is wrong: expected (%0.15f, 1), got (%0.15f, %0.0f)", 400 * math.Log(1e3), logAbs, sign)
+	}
+
+	singData := [][]float64{
+		{1, 2},
+		{2, 4},
+	}
+	sing, _ := InitMat(singData)
+	logAbs, sign, err = sing.LogDet()
+	if err != nil {
+		t.Fatal(err)
+	} else if sign != 0 || !math.IsInf(logAbs, -1) {
+		t.Fatalf("result is wrong: expected (-Inf, 0), got (%0.15f, %0.0f)", logAbs, sign)
+	} else {
+		t.Log("log det works correct")
+	}
+}
+
+func TestMatrix_InverseSingular(t *testing.T) {
+	data := [][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	}
+	mat, _ := InitMat(data)
+
+	res, err := mat.Inverse()
+	if err == nil {
+		t.Fatalf("expected singular matrix error, got\n %s", res.ToStr())
+	}
+
+	permData := [][]float64{
+		{0, 1},
+		{1, 0},
+	}
+	perm, _ := InitMat(permData)
+
+	permInv, err := perm.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	} else if !MatsEq(perm, permInv, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", perm.ToStr(), permInv.ToStr())
+	}
+
+	dataN := randDiagDominantData(200, 200, 1, 10)
+	matN, _ := InitMat(dataN)
+
+	resN, err := matN.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lapack, err := lapackInverse(dataN, len(dataN))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lapackMat, _ := InitMat(lapack)
+	if !MatsEq(resN, lapackMat, Epsilon) {
+		sub, _ := MatsSub(resN, lapackMat)
+		t.Fatalf("result is wrong: inv vs. LAPACK norm %0.15f", sub.Norm(EuclideanNorm))
+	} else {
+		t.Log("inverse matrix works correct")
+	}
+}
+
+func TestMatrix_InverseCtx(t *testing.T) {
+	dataN := randDiagDominantData(100, 100, 1, 10)
+	matN, _ := InitMat(dataN)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := matN.InverseCtx(ctx)
+	if err != context.Canceled || res != nil {
+		t.Fatalf("expected canceled error, got %v", err)
+	} else {
+		t.Log("inverse cancellation works correct")
+	}
+
+	if res, err := (&Matrix{}).InverseCtx(context.Background()); err == nil || res != nil {
+		t.Fatal("inverse of empty matrix doesn't fail")
+	}
+
+	// regular pivots, but rcond is below machine epsilon
+	illCond, _ := InitMat([][]float64{{1, 1}, {1, 1 + 3 * machEps}})
+	_, err = illCond.Inverse()
+	var singular *SingularMatrixError
+	if !errors.As(err, &singular) {
+		t.Fatalf("expected singular matrix error, got %v", err)
+	} else if singular.Rank != 1 || singular.Cond < 1 / machEps {
+		t.Fatalf("singular matrix error is wrong: rank %d, cond %e", singular.Rank, singular.Cond)
+	}
+}
+
+func TestMatrixStorage(t *testing.T) {
+	rows := init2dSlice(3, 4)
+	m := matOfRows(rows, 3, 4)
+	rows[1][2] = 5
+	if m.At(1, 2) != 5 {
+		t.Fatal("contiguous rows are copied instead of wrapped")
+	}
+
+	rows[0], rows[1] = rows[1], rows[0]
+	swapped := matOfRows(rows, 3, 4)
+	if swapped.At(0, 2) != 5 || swapped.At(1, 2) != 0 {
+		t.Fatalf("swapped rows are wrapped in storage order:\n %s", swapped.ToStr())
+	}
+
+	data := randData(6, 5, 1, 10)
+	a, _ := InitMat(data)
+	v := a.view(1, 2, 4, 3)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 3; j++ {
+			if v.At(i, j) != data[i + 1][j + 2] {
+				t.Fatalf("view is wrong at %d, %d", i, j)
+			}
+		}
+	}
+	if !MatsEq(v.Clone(), v, 1e-12) || v.Clone().stride != 3 {
+		t.Fatal("copy of a view is wrong")
+	}
+
+	at := TransposeMat(a)
+	if r, c := at.Dims(); r != 5 || c != 6 || at.At(4, 1) != data[1][4] {
+		t.Fatal("transposed matrix is wrong")
+	}
+
+	b, _ := InitMat(randData(5, 7, 1, 10))
+	res, err := MatMul(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := mat.NewDense(6, 7, nil)
+	expected.Mul(mat.NewDense(6, 5, a.data), mat.NewDense(5, 7, b.data))
+	expectedMat := &Matrix{expected.RawMatrix().Data, 6, 7, 7}
+	if !MatsEq(expectedMat, res, 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", expectedMat.ToStr(), res.ToStr())
+	} else {
+		t.Log("flat matrix storage works correct")
+	}
+}
+
+func benchmarkMatMul(b *testing.B, n int) {
+	x, _ := InitMat(randData(n, n, 1, 10))
+	y, _ := InitMat(randData(n, n, 1, 10))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = MatMul(x, y)
+	}
+}
+
+func BenchmarkMatMul64(b *testing.B)  { benchmarkMatMul(b, 64) }
+func BenchmarkMatMul256(b *testing.B) { benchmarkMatMul(b, 256) }
+func BenchmarkMatMul512(b *testing.B) { benchmarkMatMul(b, 512) }
+
+func TestMatsSlice(t *testing.T) {
+	data := [][]float64{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+	m, _ := InitMat(data)
+
+	expected, _ := InitMat([][]float64{
+		{6, 7, 8},
+		{10, 11, 12},
+	})
+	res, err := MatsSlice(m, 1, 3, 1, 4)
+	if err != nil {
+		t.Fatal(err)
+	} else if !MatsEq(expected, res, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", expected.ToStr(), res.ToStr())
+	}
+
+	res.data[0] = 100
+	if m.At(1, 1) != 6 {
+		t.Fatal("slice isn't a copy")
+	}
+	if _, err := MatsSlice(m, 2, 1, 0, 1); err == nil {
+		t.Fatal("expected out of bounds error")
+	} else {
+		t.Log("matrix slice works correct")
+	}
+}
+
+func TestView(t *testing.T) {
+	data := randData(7, 5, 1, 10)
+	m, _ := InitMat(data)
+
+	v, err := m.View(2, 1, 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := MatsSlice(m, 2, 6, 1, 4)
+	if !MatsEq(expected, v, 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", expected.ToStr(), v.ToStr())
+	}
+	vv, _ := v.View(1, 1, 2, 2)
+	vv.data[0] = -1
+	if m.At(3, 2) != -1 {
+		t.Fatal("view doesn't alias its parent")
+	}
+	if _, err := m.View(5, 0, 3, 1); err == nil {
+		t.Fatal("expected out of bounds error")
+	}
+
+	row, _ := m.Row(3)
+	row[4] = -2
+	col, _ := m.Col(2)
+	if m.At(3, 4) != -2 || len(col) != 7 || col[3] != -1 || col[6] != data[6][2] {
+		t.Fatal("row or column is wrong")
+	}
+	if diag := v.Diag(); len(diag) != 3 || diag[1] != -1 || diag[2] != data[4][3] {
+		t.Fatalf("diagonal is wrong: %s", VectToStr(diag))
+	}
+
+	blocks, err := m.Block(3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r0 := 0
+	for bi, rows := range []int{3, 2, 2} {
+		c0 := 0
+		for bj, cols := range []int{3, 2} {
+			b := blocks[bi][bj]
+			if b.rows != rows || b.cols != cols || b.At(rows - 1, cols - 1) != m.At(r0 + rows - 1, c0 + cols - 1) {
+				t.Fatalf("block %d, %d is wrong", bi, bj)
+			}
+			c0 += cols
+		}
+		r0 += rows
+	}
+	if _, err := m.Block(8, 1); err == nil {
+		t.Fatal("expected wrong number of blocks error")
+	}
+
+	c := v.Clone()
+	if !MatsEq(c, v, 1e-12) || c.stride != 3 {
+		t.Fatal("clone is wrong")
+	}
+	c.data[0] = 42
+	if err := blocks[1][1].CopyFrom(c.view(0, 0, 2, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if m.At(3, 3) != 42 || m.At(4, 4) != c.At(1, 1) {
+		t.Fatal("copy from is wrong")
+	}
+	if err := m.CopyFrom(c); err == nil {
+		t.Fatal("expected dims error")
+	} else {
+		t.Log("matrix views work correct")
+	}
+}
@@ -0,0 +1,135 @@
+package algnum
+
+import (
+	"errors"
+	"unsafe"
+)
+
+const (
+	// output tiles are mulTileRows x mulTileCols and the shared dimension
+	// is walked in mulTileDepth steps, so a tile of b stays in cache while
+	// the rows of a pass over it
+	mulTileRows  = 64
+	mulTileCols  = 256
+	mulTileDepth = 256
+	// products with fewer multiplications aren't worth the goroutines
+	mulParMinOps = 1 << 18
+)
+
+// dst = a * b, dst mustn't share storage with a or b; the output tiles are
+// computed on the default executor
+func Mul(dst, a, b *Matrix) error {
+	if a.cols != b.rows || dst.rows != a.rows || dst.cols != b.cols {
+		return errors.New("matrix dims don't match")
+	}
+	if overlaps(dst, a) || overlaps(dst, b) {
+		return errors.New("destination and operand overlap")
+	}
+
+	tileRows := (a.rows + mulTileRows - 1) / mulTileRows
+	tileCols := (b.cols + mulTileCols - 1) / mulTileCols
+	if a.rows * a.cols * b.cols < mulParMinOps {
+		matMulTo(dst, a, b)
+		return nil
+	}
+
+	DefaultExecutor().ParallelFor(tileRows * tileCols, func(t int) {
+		i0, j0 := t / tileCols * mulTileRows, t % tileCols * mulTileCols
+		mulTile(dst, a, b, i0, intMin(i0 + mulTileRows, a.rows), j0, intMin(j0 + mulTileCols, b.cols))
+	})
+
+	return nil
+}
+
+// whether a and b share elements: their storage has to intersect and, for
+// views with the same stride, so do the rectangles they cover. Views of
+// one array can only be told apart by address, hence unsafe
+func overlaps(a, b *Matrix) bool {
+	if len(a.data) == 0 || len(b.data) == 0 {
+		return false
+	}
+	size := unsafe.Sizeof(a.data[0])
+	pa, pb := uintptr(unsafe.Pointer(&a.data[0])), uintptr(unsafe.Pointer(&b.data[0]))
+	// b starts delta elements after a
+	var delta int
+	if pb >= pa {
+		delta = int((pb - pa) / size)
+	} else {
+		delta = -int((pa - pb) / size)
+	}
+	if delta >= len(a.data) || -delta >= len(b.data) {
+		return false
+	}
+	if a.stride != b.stride {
+		return true
+	}
+
+	// that is dr rows and dc columns down, or one row more and a stride less
+	// when b lies to the left of a
+	s := a.stride
+	dr, dc := delta / s, delta % s
+	if dc < 0 {
+		dr, dc = dr - 1, dc + s
+	}
+	return rectsMeet(a, b, dr, dc) || rectsMeet(a, b, dr + 1, dc - s)
+}
+
+func rectsMeet(a, b *Matrix, dr, dc int) bool {
+	return dr < a.rows && dr + b.rows > 0 && dc < a.cols && dc + b.cols > 0
+}
+
+// serial c = a * b
+func matMulTo(c, a, b *Matrix) {
+	for i0 := 0; i0 < a.rows; i0 += mulTileRows {
+		for j0 := 0; j0 < b.cols; j0 += mulTileCols {
+			mulTile(c, a, b, i0, intMin(i0 + mulTileRows, a.rows), j0, intMin(j0 + mulTileCols, b.cols))
+		}
+	}
+}
+
+// c[i0 : i1][j0 : j1] = a[i0 : i1] * b[ : ][j0 : j1]
+func mulTile(c, a, b *Matrix, i0, i1, j0, j1 int) {
+	for i := i0; i < i1; i++ {
+		ci := c.row(i)[j0 : j1]
+		for j := range ci {
+			ci[j] = 0
+		}
+	}
+
+	for k0 := 0; k0 < a.cols; k0 += mulTileDepth {
+		k1 := intMin(k0 + mulTileDepth, a.cols)
+		i := i0
+		for ; i + 4 <= i1; i += 4 {
+			mulRows4(c, a, b, i, j0, j1, k0, k1)
+		}
+		for ; i < i1; i++ {
+			ci, ai := c.row(i)[j0 : j1], a.row(i)
+			for k := k0; k < k1; k++ {
+				aik := ai[k]
+				bk := b.row(k)[j0 : j1]
+				for j, bkj := range bk {
+					ci[j] += aik * bkj
+				}
+			}
+		}
+	}
+}
+
+// register tiling: every element of b loaded is used for four rows of c
+func mulRows4(c, a, b *Matrix, i, j0, j1, k0, k1 int) {
+	c0, c1, c2, c3 := c.row(i)[j0 : j1], c.row(i + 1)[j0 : j1], c.row(i + 2)[j0 : j1], c.row(i + 3)[j0 : j1]
+	a0, a1, a2, a3 := a.row(i), a.row(i + 1), a.row(i + 2), a.row(i + 3)
+	n := len(c0)
+	c1, c2, c3 = c1[ : n], c2[ : n], c3[ : n]
+	for k := k0; k < k1; k++ {
+		a0k, a1k, a2k, a3k := a0[k], a1[k], a2[k], a3[k]
+		bk := b.row(k)[j0 : j1]
+		bk = bk[ : n]
+		for j, bkj := range bk {
+			c0[j] += a0k * bkj
+			c1[j] += a1k * bkj
+			c2[j] += a2k * bkj
+			c3[j] += a3k * bkj
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package algnum
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"testing"
+)
+
+func gonumMul(a, b *Matrix) *Matrix {
+	var res mat.Dense
+	res.Mul(mat.NewDense(a.rows, a.cols, a.Clone().data), mat.NewDense(b.rows, b.cols, b.Clone().data))
+	return &Matrix{res.RawMatrix().Data, a.rows, b.cols, b.cols}
+}
+
+func TestMul(t *testing.T) {
+	// the tiles go to several workers even on a single core
+	defer SetDefaultExecutor(DefaultExecutor())
+	SetDefaultExecutor(NewExecutor(4))
+
+	for _, dims := range [][3]int{{1, 1, 1}, {3, 5, 2}, {7, 1, 9}, {65, 300, 3}, {130, 70, 513}, {301, 257, 299}} {
+		m, k, n := dims[0], dims[1], dims[2]
+		a, _ := InitMat(randData(m, k, -9, 10))
+		b, _ := InitMat(randData(k, n, -9, 10))
+		expected := gonumMul(a, b)
+
+		res, err := MatMul(a, b)
+		if err != nil {
+			t.Fatal(err)
+		} else if !MatsEq(expected, res, 1e-9) {
+			t.Fatalf("result is wrong for %dx%d * %dx%d", m, k, k, n)
+		}
+
+		// views with strides wider than their columns
+		big := newMat(m + 2, n + 3)
+		dst, _ := big.View(1, 2, m, n)
+		pa, pb := padMat(a, m + 1, k + 2), padMat(b, k + 2, n + 1)
+		va, vb := pa.view(0, 0, m, k), pb.view(0, 0, k, n)
+		if err := Mul(dst, va, vb); err != nil {
+			t.Fatal(err)
+		} else if !MatsEq(expected, dst, 1e-9) {
+			t.Fatalf("result is wrong for views %dx%d * %dx%d", m, k, k, n)
+		}
+		if big.At(0, 0) != 0 || big.At(m + 1, n + 2) != 0 {
+			t.Fatal("product is written outside the destination view")
+		}
+	}
+
+	a, _ := InitMat(randData(3, 4, 1, 10))
+	if err := Mul(newMat(3, 3), a, a); err == nil {
+		t.Fatal("expected dims error")
+	}
+	// views of an operand: disjoint blocks are fine, shared elements aren't
+	big, _ := InitMat(randData(6, 6, 1, 10))
+	blocks, _ := big.Block(2, 2)
+	expected := gonumMul(blocks[0][0], blocks[1][0])
+	if err := Mul(blocks[0][1], blocks[0][0], blocks[1][0]); err != nil {
+		t.Fatal(err)
+	} else if !MatsEq(expected, blocks[0][1], 1e-12) {
+		t.Fatal("result is wrong for a product into a block of the operand")
+	}
+	for _, ij := range [][2]int{{1, 1}, {0, 2}, {2, 0}, {3, 2}} {
+		dst, _ := big.View(ij[0], ij[1], 3, 3)
+		if err := Mul(dst, blocks[0][0], blocks[1][1]); err == nil {
+			t.Fatalf("expected overlap error for a view at %d, %d", ij[0], ij[1])
+		}
+	}
+	col, _ := big.View(0, 5, 6, 1)
+	if err := Mul(col, big, col.Clone()); err == nil {
+		t.Fatal("expected overlap error for a column of the operand")
+	}
+
+	sq, _ := InitMat(randData(3, 3, 1, 10))
+	if err := Mul(sq, sq, sq); err == nil {
+		t.Fatal("expected overlap error")
+	} else {
+		t.Log("matrix multiplication kernel works correct")
+	}
+}
+
+func benchmarkMul(b *testing.B, n int) {
+	x, _ := InitMat(randData(n, n, 1, 10))
+	y, _ := InitMat(randData(n, n, 1, 10))
+	dst := newMat(n, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Mul(dst, x, y)
+	}
+}
+
+func benchmarkGonumMul(b *testing.B, n int) {
+	x := mat.NewDense(n, n, randFree(n * n, 1, 10))
+	y := mat.NewDense(n, n, randFree(n * n, 1, 10))
+	dst := mat.NewDense(n, n, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst.Mul(x, y)
+	}
+}
+
+func BenchmarkMul128(b *testing.B)       { benchmarkMul(b, 128) }
+func BenchmarkMul512(b *testing.B)       { benchmarkMul(b, 512) }
+func BenchmarkMul1024(b *testing.B)      { benchmarkMul(b, 1024) }
+func BenchmarkGonumMul128(b *testing.B)  { benchmarkGonumMul(b, 128) }
+func BenchmarkGonumMul512(b *testing.B)  { benchmarkGonumMul(b, 512) }
+func BenchmarkGonumMul1024(b *testing.B) { benchmarkGonumMul(b, 1024) }
@@ -0,0 +1,5 @@
+// +build !race
+
+package algnum
+
+const raceEnabled = false
@@ -0,0 +1,216 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+)
+
+// anything that can apply itself to a vector: dst = A * x and dst = Aᵀ * x,
+// dst must not alias x
+type LinearOperator interface {
+	Dims() (int, int)
+	MulVec(dst, x []float64)
+	MulVecTrans(dst, x []float64)
+}
+
+// read access to single entries, gonum mat.Matrix satisfies it as well
+type ElementAccessor interface {
+	Dims() (int, int)
+	At(i, j int) float64
+}
+
+type dimensioned interface {
+	Dims() (int, int)
+}
+
+type mulVecer interface {
+	MulVec(dst, x []float64)
+}
+
+// products of an accessor computed entry by entry
+type accessorOp struct {
+	a   ElementAccessor
+	row []float64
+}
+
+func (op *accessorOp) Dims() (int, int) {
+	return op.a.Dims()
+}
+
+func (op *accessorOp) MulVec(dst, x []float64) {
+	rows, _ := op.a.Dims()
+	for i := 0; i < rows; i++ {
+		dst[i] = dot(rowOf(op.a, i, op.row), x)
+	}
+}
+
+func (op *accessorOp) MulVecTrans(dst, x []float64) {
+	rows, cols := op.a.Dims()
+	for j := 0; j < cols; j++ {
+		dst[j] = 0
+	}
+	for i := 0; i < rows; i++ {
+		for j, aij := range rowOf(op.a, i, op.row) {
+			dst[j] += aij * x[i]
+		}
+	}
+}
+
+// a itself if it already is an operator, otherwise products are computed
+// entry by entry, which lets gonum matrices into the power iteration and Krylov solvers
+func AsOperator(a ElementAccessor) LinearOperator {
+	if op, ok := a.(LinearOperator); ok {
+		return op
+	}
+	_, cols := a.Dims()
+	return &accessorOp{a, make([]float64, cols)}
+}
+
+// row i of a, dense matrices give away their own storage, others are read into dst
+func rowOf(a ElementAccessor, i int, dst []float64) []float64 {
+	if m, ok := a.(*Matrix); ok {
+		return m.row(i)
+	}
+	for j := range dst {
+		dst[j] = a.At(i, j)
+	}
+	return dst
+}
+
+// a in CSR form, sparse matrices are converted directly, others are read
+// row by row keeping the nonzeros
+func csrOf(a ElementAccessor) *CSR {
+	switch m := a.(type) {
+	case *CSR:
+		return m
+	case *CSC:
+		return m.ToCSR()
+	}
+	rows, cols := a.Dims()
+	res := &CSR{rows: rows, cols: cols, rowPtr: make([]int, rows + 1)}
+	row := make([]float64, cols)
+	for i := 0; i < rows; i++ {
+		for j, aij := range rowOf(a, i, row) {
+			if aij != 0 {
+				res.colInd = append(res.colInd, j)
+				res.vals = append(res.vals, aij)
+			}
+		}
+		res.rowPtr[i + 1] = len(res.vals)
+	}
+	return res
+}
+
+// the row-wise solvers read a row at a time, a CSC is converted once so
+// that they don't search every column for it
+func byRows(a ElementAccessor) ElementAccessor {
+	if m, ok := a.(*CSC); ok {
+		return m.ToCSR()
+	}
+	return a
+}
+
+// sum of a[i][j] * x[j] over j != i, and a[i][i]; sparse rows visit their nonzeros only
+func rowSplitDot(a ElementAccessor, i int, x, row []float64) (float64, float64) {
+	var off, diag float64
+	if m, ok := a.(*CSR); ok {
+		ind, vals := m.Row(i)
+		for k, j := range ind {
+			if j == i {
+				diag = vals[k]
+			} else {
+				off += vals[k] * x[j]
+			}
+		}
+		return off, diag
+	}
+	for j, aij := range rowOf(a, i, row) {
+		if j == i {
+			diag = aij
+		} else {
+			off += aij * x[j]
+		}
+	}
+	return off, diag
+}
+
+func denseOf(a ElementAccessor) *Matrix {
+	rows, cols := a.Dims()
+	if m, ok := a.(*Matrix); ok {
+		return m.Clone()
+	}
+	data := init2dSlice(rows, cols)
+	for i := 0; i < rows; i++ {
+		rowOf(a, i, data[i])
+	}
+	return matOfRows(data, rows, cols)
+}
+
+// dense form of a for reading only: a *Matrix is used as is, anything
+// else is copied into storage from w, which is reported
+func denseIn(w *Workspace, a ElementAccessor) (*Matrix, bool) {
+	if m, ok := a.(*Matrix); ok {
+		return m, false
+	}
+	rows, cols := a.Dims()
+	m := w.mat(rows, cols)
+	for i := 0; i < rows; i++ {
+		rowOf(a, i, m.row(i))
+	}
+	return m, true
+}
+
+func isDiagDominant(a ElementAccessor) bool {
+	if m, ok := a.(interface{ IsDiagDominant() bool }); ok {
+		return m.IsDiagDominant()
+	}
+	rows, cols := a.Dims()
+	if rows != cols {
+		return false
+	}
+	row := make([]float64, cols)
+	for i := 0; i < rows; i++ {
+		var sum float64
+		for j, aij := range rowOf(a, i, row) {
+			if j != i {
+				sum += math.Abs(aij)
+			}
+		}
+		if math.Abs(row[i]) < sum {
+			return false
+		}
+	}
+	return true
+}
+
+func isSymmetric(a ElementAccessor) bool {
+	if m, ok := a.(interface{ IsSymmetric() bool }); ok {
+		return m.IsSymmetric()
+	}
+	rows, cols := a.Dims()
+	if rows != cols {
+		return false
+	}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < i; j++ {
+			if a.At(i, j) != a.At(j, i) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func checkOperator(a dimensioned, f []float64) error {
+	rows, cols := a.Dims()
+	if rows != len(f) {
+		return errors.New("matrix and free element dims don't match")
+	}
+	if rows == 0 {
+		return errors.New("matrix or free element is empty")
+	}
+	if rows != cols {
+		return errors.New("matrix isn't square")
+	}
+	return nil
+}
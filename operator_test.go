@@ -0,0 +1,115 @@
+package algnum
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"testing"
+)
+
+func TestLinearOperator(t *testing.T) {
+	data := randData(20, 20, -10, 10)
+	m, _ := InitMat(data)
+	x := randFree(20, -10, 10)
+
+	var op LinearOperator = m
+	var acc ElementAccessor = m
+	if acc.At(3, 5) != data[3][5] {
+		t.Fatalf("result is wrong: expected %f, got %f", data[3][5], acc.At(3, 5))
+	}
+
+	expected, _ := MatVecMul(m, x)
+	res := make([]float64, 20)
+	op.MulVec(res, x)
+	if !VectsEq(expected, res, 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expected), VectToStr(res))
+	}
+
+	expectedT, _ := MatVecMul(TransposeMat(m), x)
+	op.MulVecTrans(res, x)
+	if !VectsEq(expectedT, res, 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedT), VectToStr(res))
+	}
+
+	var flat []float64
+	for i := range data {
+		flat = append(flat, data[i]...)
+	}
+	gonumOp := AsOperator(mat.NewDense(20, 20, flat))
+	gonumOp.MulVec(res, x)
+	if !VectsEq(expected, res, 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expected), VectToStr(res))
+	}
+	gonumOp.MulVecTrans(res, x)
+	if !VectsEq(expectedT, res, 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedT), VectToStr(res))
+	} else {
+		t.Log("linear operator works correct")
+	}
+}
+
+func TestSolversOnGonum(t *testing.T) {
+	data := randDiagSym(40, 40, 1, 10)
+	f := randFree(40, 1, 10)
+	var flat []float64
+	for i := range data {
+		flat = append(flat, data[i]...)
+	}
+	dense := mat.NewDense(40, 40, flat)
+
+	lapack, err := lapackSolve(data, 40, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, solve := range map[string]func(ElementAccessor, []float64, *SolveOptions) (*SolveResult, error){
+		"jacobi": Jacobi,
+		"seidel": Seidel,
+	} {
+		res, err := solve(dense, f, nil)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(lapack, res.X, 1e-6) {
+			t.Fatalf("%s result is wrong: expected\n %s,\ngot\n %s", name, VectToStr(lapack), VectToStr(res.X))
+		}
+	}
+
+	res, err := SOR(dense, f, 1.2, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, res.X, 1e-6) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(res.X))
+	}
+
+	ic, err := InitICPreconditioner(dense)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cg, err := ConjugateGradient(AsOperator(dense), f, &SolveOptions{Precond: ic})
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, cg.X, 1e-6) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(cg.X))
+	}
+
+	fp, err := FixedPointIteration(dense, f, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, fp.X, 1e-4) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(fp.X))
+	}
+
+	vals := lapackEigenSym(data, 40)
+	pairs, err := PowerIteration(AsOperator(dense), nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if d := pairs[0].Value - vals[39]; d > 1e-6 || d < -1e-6 {
+		t.Fatalf("result is wrong: expected %0.15f, got %0.15f", vals[39], pairs[0].Value)
+	}
+	p, err := InverseIteration(dense, &PowerOptions{Shift: vals[0] - 0.1})
+	if err != nil {
+		t.Fatal(err)
+	} else if d := p.Value - vals[0]; d > 1e-6 || d < -1e-6 {
+		t.Fatalf("result is wrong: expected %0.15f, got %0.15f", vals[0], p.Value)
+	} else {
+		t.Log("solvers on gonum matrices work correct")
+	}
+}
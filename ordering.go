@@ -0,0 +1,379 @@
+package algnum
+
+import (
+	"errors"
+	"sort"
+)
+
+const (
+	NaturalOrdering = iota
+	AMDOrdering
+	RCMOrdering
+	NestedDissectionOrdering
+)
+
+// parts at most this small aren't dissected any further
+const ndLeafSize = 16
+
+// fill-reducing symmetric ordering of a: row and column i of the permuted
+// matrix are row and column p[i] of a; the adjacency graph is that of A + Aᵀ
+func Ordering(a *CSR, kind int) (Permutation, error) {
+	if a.rows != a.cols {
+		return nil, errors.New("matrix isn't square")
+	}
+	switch kind {
+	case NaturalOrdering:
+		return IdentityPerm(a.rows), nil
+	case AMDOrdering:
+		return AMD(a), nil
+	case RCMOrdering:
+		return RCM(a), nil
+	case NestedDissectionOrdering:
+		return NestedDissection(a), nil
+	}
+	return nil, errors.New("unknown ordering")
+}
+
+// sorted neighbours of every node in the graph of A + Aᵀ, without self loops
+func adjacency(a *CSR) [][]int {
+	n := a.rows
+	sets := make([]map[int]bool, n)
+	for i := range sets {
+		sets[i] = map[int]bool{}
+	}
+	for i := 0; i < n; i++ {
+		for k := a.rowPtr[i]; k < a.rowPtr[i + 1]; k++ {
+			if j := a.colInd[k]; j != i {
+				sets[i][j] = true
+				sets[j][i] = true
+			}
+		}
+	}
+
+	adj := make([][]int, n)
+	for i, set := range sets {
+		for j := range set {
+			adj[i] = append(adj[i], j)
+		}
+		sort.Ints(adj[i])
+	}
+	return adj
+}
+
+// approximate minimum degree (Amestoy, Davis, Duff) on the quotient graph:
+// an eliminated node becomes an element holding the variables it connects,
+// so fill is never formed explicitly, and the degree of a variable is the
+// AMD upper bound computed from the element sizes instead of the exact one.
+// Variables wait in degree lists; supervariables and aggressive absorption
+// are left out
+func AMD(a *CSR) Permutation {
+	n := a.rows
+	// variable neighbours, element neighbours, and the variables of every element
+	adj := adjacency(a)
+	elems := make([][]int, n)
+	vars := make([][]int, n)
+	eliminated, absorbed := make([]bool, n), make([]bool, n)
+
+	deg := make([]int, n)
+	head, next, prev := make([]int, n), make([]int, n), make([]int, n)
+	for d := range head {
+		head[d] = -1
+	}
+	insert := func(i int) {
+		d := deg[i]
+		next[i], prev[i] = head[d], -1
+		if head[d] >= 0 {
+			prev[head[d]] = i
+		}
+		head[d] = i
+	}
+	remove := func(i int) {
+		if prev[i] >= 0 {
+			next[prev[i]] = next[i]
+		} else {
+			head[deg[i]] = next[i]
+		}
+		if next[i] >= 0 {
+			prev[next[i]] = prev[i]
+		}
+	}
+	for i := 0; i < n; i++ {
+		deg[i] = len(adj[i])
+		insert(i)
+	}
+
+	// mark[i] == stamp tells i is in the current element,
+	// w[e] is |L_e \ L_p| while computing degrees and -1 otherwise
+	mark, w := make([]int, n), make([]int, n)
+	for e := range w {
+		w[e] = -1
+	}
+	var stamp, minDeg int
+	var touched []int
+
+	perm := make(Permutation, 0, n)
+	for k := 0; k < n; k++ {
+		for head[minDeg] < 0 {
+			minDeg++
+		}
+		p := head[minDeg]
+		remove(p)
+		eliminated[p] = true
+		perm = append(perm, p)
+
+		// L_p: the variables next to p and those of the elements p absorbs
+		stamp++
+		mark[p] = stamp
+		var lp []int
+		for _, i := range adj[p] {
+			if !eliminated[i] && mark[i] != stamp {
+				mark[i] = stamp
+				lp = append(lp, i)
+			}
+		}
+		for _, e := range elems[p] {
+			if absorbed[e] {
+				continue
+			}
+			for _, i := range vars[e] {
+				if mark[i] != stamp {
+					mark[i] = stamp
+					lp = append(lp, i)
+				}
+			}
+			absorbed[e], vars[e] = true, nil
+		}
+		vars[p], adj[p], elems[p] = lp, nil, nil
+
+		touched = touched[ : 0]
+		for _, i := range lp {
+			for _, e := range elems[i] {
+				if absorbed[e] {
+					continue
+				}
+				if w[e] < 0 {
+					w[e] = len(vars[e])
+					touched = append(touched, e)
+				}
+				w[e]--
+			}
+		}
+
+		alive := n - k - 1
+		for _, i := range lp {
+			remove(i)
+			// p covers the edges inside L_p, so they leave the variable list
+			vs := adj[i][ : 0]
+			for _, j := range adj[i] {
+				if mark[j] != stamp && !eliminated[j] {
+					vs = append(vs, j)
+				}
+			}
+			adj[i] = vs
+			ext := len(vs)
+			es := elems[i][ : 0]
+			for _, e := range elems[i] {
+				if !absorbed[e] {
+					es = append(es, e)
+					ext += w[e]
+				}
+			}
+			elems[i] = append(es, p)
+
+			deg[i] = intMin(alive - 1, intMin(deg[i], ext) + len(lp) - 1)
+			insert(i)
+			minDeg = intMin(minDeg, deg[i])
+		}
+		for _, e := range touched {
+			w[e] = -1
+		}
+	}
+
+	return perm
+}
+
+// reverse Cuthill-McKee, every connected component is started from a
+// pseudo-peripheral node and neighbours are visited by increasing degree
+func RCM(a *CSR) Permutation {
+	n := a.rows
+	adj := adjacency(a)
+	nodes := make([]int, n)
+	for i := range nodes {
+		nodes[i] = i
+	}
+
+	var order []int
+	in, seen := make([]bool, n), make([]bool, n)
+	for _, comp := range components(adj, nodes, in, seen) {
+		markNodes(in, comp, true)
+		order = append(order, cuthillMcKee(adj, in, pseudoPeripheral(adj, in, comp))...)
+		markNodes(in, comp, false)
+	}
+
+	perm := make(Permutation, n)
+	for i, v := range order {
+		perm[n - 1 - i] = v
+	}
+	return perm
+}
+
+// nested dissection by level structure bisection: the middle BFS level
+// from a pseudo-peripheral node is the separator and is numbered last
+func NestedDissection(a *CSR) Permutation {
+	n := a.rows
+	adj := adjacency(a)
+	nodes := make([]int, n)
+	for i := range nodes {
+		nodes[i] = i
+	}
+
+	// marks shared by all levels of the recursion, cleared after every use
+	in, seen := make([]bool, n), make([]bool, n)
+	perm := make(Permutation, 0, n)
+	var dissect func(nodes []int)
+	dissect = func(nodes []int) {
+		if len(nodes) == 0 {
+			return
+		}
+		comps := components(adj, nodes, in, seen)
+		if len(comps) > 1 {
+			for _, comp := range comps {
+				dissect(comp)
+			}
+			return
+		}
+
+		markNodes(in, nodes, true)
+		levels := levelStructure(adj, in, pseudoPeripheral(adj, in, nodes))
+		if len(nodes) <= ndLeafSize || len(levels) < 3 {
+			perm = append(perm, cuthillMcKee(adj, in, levels[0][0])...)
+			markNodes(in, nodes, false)
+			return
+		}
+		markNodes(in, nodes, false)
+
+		mid := len(levels) / 2
+		var first, second []int
+		for l := 0; l < mid; l++ {
+			first = append(first, levels[l]...)
+		}
+		for l := mid + 1; l < len(levels); l++ {
+			second = append(second, levels[l]...)
+		}
+		dissect(first)
+		dissect(second)
+		perm = append(perm, levels[mid]...)
+	}
+	dissect(nodes)
+
+	return perm
+}
+
+func markNodes(in []bool, nodes []int, v bool) {
+	for _, i := range nodes {
+		in[i] = v
+	}
+}
+
+// connected components of the subgraph induced by nodes; in and seen are
+// marks over all nodes, false on entry and left false
+func components(adj [][]int, nodes []int, in, seen []bool) [][]int {
+	markNodes(in, nodes, true)
+	var comps [][]int
+	for _, s := range nodes {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		comp := []int{s}
+		for k := 0; k < len(comp); k++ {
+			for _, j := range adj[comp[k]] {
+				if in[j] && !seen[j] {
+					seen[j] = true
+					comp = append(comp, j)
+				}
+			}
+		}
+		comps = append(comps, comp)
+	}
+	markNodes(in, nodes, false)
+	markNodes(seen, nodes, false)
+	return comps
+}
+
+// BFS levels from start within the nodes marked in
+func levelStructure(adj [][]int, in []bool, start int) [][]int {
+	level := map[int]bool{start: true}
+	levels := [][]int{{start}}
+	for {
+		var next []int
+		for _, v := range levels[len(levels) - 1] {
+			for _, j := range adj[v] {
+				if in[j] && !level[j] {
+					level[j] = true
+					next = append(next, j)
+				}
+			}
+		}
+		if len(next) == 0 {
+			return levels
+		}
+		levels = append(levels, next)
+	}
+}
+
+func inDegree(adj [][]int, in []bool, v int) int {
+	d := 0
+	for _, j := range adj[v] {
+		if in[j] {
+			d++
+		}
+	}
+	return d
+}
+
+// George-Liu: restart from a minimum degree node of the last level while
+// the level structure gets deeper
+func pseudoPeripheral(adj [][]int, in []bool, nodes []int) int {
+	start := nodes[0]
+	for _, v := range nodes {
+		if inDegree(adj, in, v) < inDegree(adj, in, start) {
+			start = v
+		}
+	}
+
+	levels := levelStructure(adj, in, start)
+	for {
+		last := levels[len(levels) - 1]
+		cand := last[0]
+		for _, v := range last {
+			if inDegree(adj, in, v) < inDegree(adj, in, cand) {
+				cand = v
+			}
+		}
+		candLevels := levelStructure(adj, in, cand)
+		if len(candLevels) <= len(levels) {
+			return start
+		}
+		start, levels = cand, candLevels
+	}
+}
+
+func cuthillMcKee(adj [][]int, in []bool, start int) []int {
+	seen := map[int]bool{start: true}
+	order := []int{start}
+	for k := 0; k < len(order); k++ {
+		var nbrs []int
+		for _, j := range adj[order[k]] {
+			if in[j] && !seen[j] {
+				seen[j] = true
+				nbrs = append(nbrs, j)
+			}
+		}
+		sort.SliceStable(nbrs, func(x, y int) bool {
+			return inDegree(adj, in, nbrs[x]) < inDegree(adj, in, nbrs[y])
+		})
+		order = append(order, nbrs...)
+	}
+	return order
+}
@@ -0,0 +1,113 @@
+package algnum
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func isPerm(p Permutation, n int) bool {
+	if len(p) != n {
+		return false
+	}
+	seen := make([]bool, n)
+	for _, v := range p {
+		if v < 0 || v >= n || seen[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	return true
+}
+
+func permuteCSR(a *CSR, p Permutation) *CSR {
+	pinv := p.Inverse()
+	coo, _ := InitCOO(a.rows, a.cols)
+	for i := 0; i < a.rows; i++ {
+		ind, vals := a.Row(i)
+		for k, j := range ind {
+			_ = coo.Append(pinv[i], pinv[j], vals[k])
+		}
+	}
+	return coo.ToCSR()
+}
+
+func bandwidth(a *CSR) int {
+	res := 0
+	for i := 0; i < a.rows; i++ {
+		ind, _ := a.Row(i)
+		for _, j := range ind {
+			res = intMax(res, intMax(i - j, j - i))
+		}
+	}
+	return res
+}
+
+func TestOrderings(t *testing.T) {
+	k := 12
+	n := k * k
+	lap := laplacian2dCSR(k)
+	shuffled := permuteCSR(lap, Permutation(rand.Perm(n)))
+
+	for _, kind := range []int{NaturalOrdering, AMDOrdering, RCMOrdering, NestedDissectionOrdering} {
+		p, err := Ordering(shuffled, kind)
+		if err != nil {
+			t.Fatal(err)
+		} else if !isPerm(p, n) {
+			t.Fatalf("ordering %d isn't a permutation", kind)
+		}
+	}
+
+	rcm, _ := Ordering(shuffled, RCMOrdering)
+	if bw := bandwidth(permuteCSR(shuffled, rcm)); bw > 2 * k {
+		t.Fatalf("rcm bandwidth is %d, expected at most %d", bw, 2 * k)
+	}
+
+	// two components
+	coo, _ := InitCOO(6, 6)
+	for _, e := range [][2]int{{0, 2}, {2, 4}, {1, 3}, {3, 5}} {
+		_ = coo.Append(e[0], e[1], 1)
+	}
+	for i := 0; i < 6; i++ {
+		_ = coo.Append(i, i, 1)
+	}
+	for _, kind := range []int{AMDOrdering, RCMOrdering, NestedDissectionOrdering} {
+		p, _ := Ordering(coo.ToCSR(), kind)
+		if !isPerm(p, 6) {
+			t.Fatalf("ordering %d isn't a permutation on a disconnected graph", kind)
+		}
+	}
+
+	// empty matrix
+	empty, _ := InitCOO(0, 0)
+	for _, kind := range []int{NaturalOrdering, AMDOrdering, RCMOrdering, NestedDissectionOrdering} {
+		if p, err := Ordering(empty.ToCSR(), kind); err != nil {
+			t.Fatal(err)
+		} else if len(p) != 0 {
+			t.Fatalf("ordering %d of an empty matrix has %d entries", kind, len(p))
+		}
+		// mustn't panic
+		_, _ = FactorizeSparseLU(empty.ToCSR(), &SparseLUOptions{Ordering: kind})
+	}
+
+	// arrow matrix: eliminating the dense node early fills everything,
+	// minimum degree keeps it for the end
+	m := 40
+	arrow, _ := InitCOO(m, m)
+	for i := 0; i < m; i++ {
+		_ = arrow.Append(i, i, 4)
+		if i > 0 {
+			_ = arrow.Append(0, i, 1)
+			_ = arrow.Append(i, 0, 1)
+		}
+	}
+	amd := AMD(arrow.ToCSR())
+	if pos := amd.Inverse()[0]; pos < m - 2 {
+		t.Fatalf("amd eliminates the dense node at step %d of %d", pos, m)
+	}
+
+	if _, err := Ordering(shuffled, 42); err == nil {
+		t.Fatal("expected unknown ordering error")
+	} else {
+		t.Log("orderings work correct")
+	}
+}
@@ -0,0 +1,338 @@
+package algnum
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+type SingularMatrixError struct {
+	Rank int
+	Cond float64
+}
+
+func (e *SingularMatrixError) Error() string {
+	if e.Cond != 0 {
+		return fmt.Sprintf("matrix is singular to working precision: condition number %e, numerical rank %d", e.Cond, e.Rank)
+	}
+	return fmt.Sprintf("matrix is singular: numerical rank %d", e.Rank)
+}
+
+// row i of PA is row p[i] of A, column j of AQ is column q[j] of A
+type Permutation []int
+
+func IdentityPerm(n int) Permutation {
+	p := make(Permutation, n)
+	for i := range p {
+		p[i] = i
+	}
+	return p
+}
+
+func (p Permutation) Sign() float64 {
+	visited := make([]bool, len(p))
+	sign := float64(1)
+	for i := range p {
+		if visited[i] {
+			continue
+		}
+		cycleLen := 0
+		for j := i; !visited[j]; j = p[j] {
+			visited[j] = true
+			cycleLen++
+		}
+		if cycleLen % 2 == 0 {
+			sign = -sign
+		}
+	}
+	return sign
+}
+
+func (p Permutation) Inverse() Permutation {
+	res := make(Permutation, len(p))
+	for i, pi := range p {
+		res[pi] = i
+	}
+	return res
+}
+
+func (p Permutation) Mat() *Matrix {
+	res, _ := InitMatOfDim(len(p))
+	for i, pi := range p {
+		res.data[i * res.stride + pi] = 1
+	}
+	return res
+}
+
+func (p Permutation) PermuteVec(x []float64) ([]float64, error) {
+	if len(x) != len(p) {
+		return nil, errors.New("permutation and vector dims don't match")
+	}
+
+	res := make([]float64, len(x))
+	for i, pi := range p {
+		res[i] = x[pi]
+	}
+	return res, nil
+}
+
+func (p Permutation) InversePermuteVec(x []float64) ([]float64, error) {
+	if len(x) != len(p) {
+		return nil, errors.New("permutation and vector dims don't match")
+	}
+
+	res := make([]float64, len(x))
+	for i, pi := range p {
+		res[pi] = x[i]
+	}
+	return res, nil
+}
+
+// LU with partial pivoting done in place on a copy of data:
+// the strict lower part holds L (unit diagonal), the upper part holds U,
+// row i of LU is row perm[i] of data, sign is the sign of the permutation
+func pivotedLU(data [][]float64) ([][]float64, []int, float64) {
+	lu := copy2dSlice(data)
+	perm := make([]int, len(lu))
+	return lu, perm, pivotedLUTo(lu, perm)
+}
+
+// pivotedLU in the storage given, lu holds the matrix on entry
+func pivotedLUTo(lu [][]float64, perm []int) float64 {
+	n := len(lu)
+	sign := float64(1)
+
+	for i := range perm {
+		perm[i] = i
+	}
+
+	for k := 0; k < n; k++ {
+		p := k
+		max := math.Abs(lu[k][k])
+		for i := k + 1; i < n; i++ {
+			if math.Abs(lu[i][k]) > max {
+				max = math.Abs(lu[i][k])
+				p = i
+			}
+		}
+
+		if max == 0 {
+			continue
+		}
+
+		if p != k {
+			lu[p], lu[k] = lu[k], lu[p]
+			perm[p], perm[k] = perm[k], perm[p]
+			sign = -sign
+		}
+
+		for i := k + 1; i < n; i++ {
+			c := lu[i][k] / lu[k][k]
+			lu[i][k] = c
+			if c == 0 {
+				continue
+			}
+			for j := k + 1; j < n; j++ {
+				lu[i][j] -= c * lu[k][j]
+			}
+		}
+	}
+
+	return sign
+}
+
+func pivotedLUSolve(lu [][]float64, perm []int, b, x []float64) {
+	n := len(lu)
+	for i := 0; i < n; i++ {
+		sum := b[perm[i]]
+		for k := 0; k < i; k++ {
+			sum -= lu[i][k] * x[k]
+		}
+		x[i] = sum
+	}
+	for i := n - 1; i >= 0; i-- {
+		sum := x[i]
+		for k := i + 1; k < n; k++ {
+			sum -= lu[i][k] * x[k]
+		}
+		x[i] = sum / lu[i][i]
+	}
+}
+
+// pivots negligible against the entries of A and the growth of U
+// count as zero
+func pivotedLURank(data, lu [][]float64) int {
+	n := len(lu)
+	tol := float64(n) * machEps * math.Max(maxAbs(data), maxAbsUpper(lu))
+
+	rank := 0
+	for i := 0; i < n; i++ {
+		if math.Abs(lu[i][i]) > tol {
+			rank++
+		}
+	}
+	return rank
+}
+
+func splitLU(lu [][]float64) (*Matrix, *Matrix) {
+	n := len(lu)
+	lData, uData := init2dSlice(n, n), init2dSlice(n, n)
+	for i := 0; i < n; i++ {
+		lData[i][i] = 1
+		copy(lData[i][:i], lu[i][:i])
+		copy(uData[i][i:], lu[i][i:])
+	}
+	return matOfRows(lData, n, n), matOfRows(uData, n, n)
+}
+
+func PLU(m *Matrix) (Permutation, *Matrix, *Matrix, int, error) {
+	if !m.IsSquare() {
+		return nil, nil, nil, 0, errors.New("matrix isn't square")
+	}
+	n := m.rows
+
+	lu, perm, _ := pivotedLU(m.rowSlices())
+	rank := pivotedLURank(m.rowSlices(), lu)
+	if rank < n {
+		return nil, nil, nil, rank, &SingularMatrixError{Rank: rank}
+	}
+
+	l, u := splitLU(lu)
+
+	return perm, l, u, rank, nil
+}
+
+func PLUQ(m *Matrix) (Permutation, *Matrix, *Matrix, Permutation, int, error) {
+	if !m.IsSquare() {
+		return nil, nil, nil, nil, 0, errors.New("matrix isn't square")
+	}
+	n := m.rows
+
+	lu := copy2dSlice(m.rowSlices())
+	p, q := IdentityPerm(n), IdentityPerm(n)
+	tol := float64(n) * machEps * maxAbs(m.rowSlices())
+
+	rank := 0
+	for k := 0; k < n; k++ {
+		pr, pc := k, k
+		max := float64(0)
+		for i := k; i < n; i++ {
+			for j := k; j < n; j++ {
+				if math.Abs(lu[i][j]) > max {
+					max = math.Abs(lu[i][j])
+					pr, pc = i, j
+				}
+			}
+		}
+
+		// the rest of the matrix is negligible, so is every pivot to come
+		if max <= tol {
+			break
+		}
+		rank++
+
+		if pr != k {
+			lu[pr], lu[k] = lu[k], lu[pr]
+			p[pr], p[k] = p[k], p[pr]
+		}
+		if pc != k {
+			for i := 0; i < n; i++ {
+				lu[i][pc], lu[i][k] = lu[i][k], lu[i][pc]
+			}
+			q[pc], q[k] = q[k], q[pc]
+		}
+
+		for i := k + 1; i < n; i++ {
+			c := lu[i][k] / lu[k][k]
+			lu[i][k] = c
+			for j := k + 1; j < n; j++ {
+				lu[i][j] -= c * lu[k][j]
+			}
+		}
+	}
+
+	if rank < n {
+		return nil, nil, nil, nil, rank, &SingularMatrixError{Rank: rank}
+	}
+
+	l, u := splitLU(lu)
+
+	return p, l, u, q, rank, nil
+}
+
+func DetPLU(p Permutation, u *Matrix) (float64, error) {
+	if !u.IsSquare() {
+		return -1, errors.New("matrix isn't square")
+	}
+	if len(p) != u.rows {
+		return -1, errors.New("permutation and matrix dims don't match")
+	}
+
+	res := p.Sign()
+	for i := 0; i < u.rows; i++ {
+		res *= u.data[i * u.stride + i]
+	}
+
+	return res, nil
+}
+
+func DetPLUQ(p Permutation, u *Matrix, q Permutation) (float64, error) {
+	if len(q) != u.rows {
+		return -1, errors.New("permutation and matrix dims don't match")
+	}
+
+	d, err := DetPLU(p, u)
+	if err != nil {
+		return -1, err
+	}
+
+	return d * q.Sign(), nil
+}
+
+func PLUSolve(p Permutation, l, u *Matrix, f []float64) ([]float64, error) {
+	pf, err := p.PermuteVec(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return LUSolve(l, u, pf)
+}
+
+func PLUQSolve(p Permutation, l, u *Matrix, q Permutation, f []float64) ([]float64, error) {
+	z, err := PLUSolve(p, l, u, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.InversePermuteVec(z)
+}
+
+func InversePLU(p Permutation, l, u *Matrix) (*Matrix, error) {
+	return InversePLUQ(p, l, u, IdentityPerm(len(p)))
+}
+
+func InversePLUQ(p Permutation, l, u *Matrix, q Permutation) (*Matrix, error) {
+	n := u.rows
+	if !l.IsSquare() || !u.IsSquare() || l.rows != n {
+		return nil, errors.New("factors dims don't match")
+	}
+	if len(p) != n || len(q) != n {
+		return nil, errors.New("permutation and matrix dims don't match")
+	}
+
+	resData := init2dSlice(n, n)
+	e := make([]float64, n)
+	for j := 0; j < n; j++ {
+		e[j] = 1
+		x, err := PLUQSolve(p, l, u, q, e)
+		if err != nil {
+			return nil, err
+		}
+		e[j] = 0
+		for i := 0; i < n; i++ {
+			resData[i][j] = x[i]
+		}
+	}
+
+	return matOfRows(resData, n, n), nil
+}
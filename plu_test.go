@@ -0,0 +1,173 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPLU(t *testing.T) {
+	data := [][]float64{
+		{0, 1},
+		{1, 0},
+	}
+	mat, _ := InitMat(data)
+
+	p, l, u, rank, err := PLU(mat)
+	if err != nil {
+		t.Fatal(err)
+	} else if rank != 2 {
+		t.Fatalf("rank is wrong: expected %d, got %d", 2, rank)
+	}
+	pa, _ := MatMul(p.Mat(), mat)
+	lu, _ := MatMul(l, u)
+	if !MatsEq(pa, lu, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", pa.ToStr(), lu.ToStr())
+	}
+
+	dataN := randData(50, 50, 1, 10)
+	matN, _ := InitMat(dataN)
+
+	pN, lN, uN, _, err := PLU(matN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paN, _ := MatMul(pN.Mat(), matN)
+	luN, _ := MatMul(lN, uN)
+	if !MatsEq(paN, luN, 1e-9) {
+		sub, _ := MatsSub(paN, luN)
+		t.Fatalf("result is wrong: PA - LU norm %0.15f", sub.Norm(EuclideanNorm))
+	} else {
+		t.Log("PLU works correct")
+	}
+
+	singData := [][]float64{
+		{1, 2, 3},
+		{2, 4, 6},
+		{1, 0, 1},
+	}
+	sing, _ := InitMat(singData)
+
+	_, _, _, rank, err = PLU(sing)
+	var singErr *SingularMatrixError
+	if !errors.As(err, &singErr) {
+		t.Fatalf("expected singular matrix error, got %v", err)
+	} else if rank != 2 || singErr.Rank != 2 {
+		t.Fatalf("rank is wrong: expected %d, got %d", 2, rank)
+	}
+}
+
+func TestPLUQ(t *testing.T) {
+	dataN := randData(50, 50, 1, 10)
+	matN, _ := InitMat(dataN)
+
+	p, l, u, q, rank, err := PLUQ(matN)
+	if err != nil {
+		t.Fatal(err)
+	} else if rank != 50 {
+		t.Fatalf("rank is wrong: expected %d, got %d", 50, rank)
+	}
+	pa, _ := MatMul(p.Mat(), matN)
+	paq, _ := MatMul(pa, TransposeMat(q.Mat()))
+	lu, _ := MatMul(l, u)
+	if !MatsEq(paq, lu, 1e-9) {
+		sub, _ := MatsSub(paq, lu)
+		t.Fatalf("result is wrong: PAQ - LU norm %0.15f", sub.Norm(EuclideanNorm))
+	}
+
+	singData := [][]float64{
+		{1, 2, 3, 4},
+		{2, 4, 6, 8},
+		{0, 1, 0, 1},
+		{1, 3, 3, 5},
+	}
+	sing, _ := InitMat(singData)
+
+	_, _, _, _, rank, err = PLUQ(sing)
+	var singErr *SingularMatrixError
+	if !errors.As(err, &singErr) {
+		t.Fatalf("expected singular matrix error, got %v", err)
+	} else if rank != 2 {
+		t.Fatalf("rank is wrong: expected %d, got %d", 2, rank)
+	} else {
+		t.Log("PLUQ works correct")
+	}
+}
+
+func TestPLUSolve(t *testing.T) {
+	dataN := randData(100, 100, 1, 10)
+	matN, _ := InitMat(dataN)
+	fN := randFree(100, 1, 10)
+
+	lapack, err := lapackSolve(dataN, len(dataN), fN)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, l, u, _, err := PLU(matN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := PLUSolve(p, l, u, fN)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(res, lapack, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(res))
+	}
+
+	pq, lq, uq, q, _, err := PLUQ(matN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resQ, err := PLUQSolve(pq, lq, uq, q, fN)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(resQ, lapack, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(resQ))
+	} else {
+		t.Log("PLU-solve works correct")
+	}
+}
+
+func TestDetInversePLU(t *testing.T) {
+	dataN := randData(10, 10, 1, 10)
+	matN, _ := InitMat(dataN)
+	lapackD := lapackDet(dataN, len(dataN))
+	lapackInv, err := lapackInverse(dataN, len(dataN))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lapackInvMat, _ := InitMat(lapackInv)
+
+	p, l, u, _, err := PLU(matN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, _ := DetPLU(p, u)
+	if math.Abs(d - lapackD) > 1e-8 * math.Abs(lapackD) {
+		t.Fatalf("det is wrong: expected %0.15f, got %0.15f", lapackD, d)
+	}
+	inv, err := InversePLU(p, l, u)
+	if err != nil {
+		t.Fatal(err)
+	} else if !MatsEq(inv, lapackInvMat, Epsilon) {
+		t.Fatalf("inverse is wrong: expected\n %s,\ngot\n %s", lapackInvMat.ToStr(), inv.ToStr())
+	}
+
+	pq, lq, uq, q, _, err := PLUQ(matN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dq, _ := DetPLUQ(pq, uq, q)
+	if math.Abs(dq - lapackD) > 1e-8 * math.Abs(lapackD) {
+		t.Fatalf("det is wrong: expected %0.15f, got %0.15f", lapackD, dq)
+	}
+	invQ, err := InversePLUQ(pq, lq, uq, q)
+	if err != nil {
+		t.Fatal(err)
+	} else if !MatsEq(invQ, lapackInvMat, Epsilon) {
+		t.Fatalf("inverse is wrong: expected\n %s,\ngot\n %s", lapackInvMat.ToStr(), invQ.ToStr())
+	} else {
+		t.Log("PLU det and inverse work correct")
+	}
+}
@@ -0,0 +1,335 @@
+package algnum
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+const powerMaxIter = 10000
+
+// zero fields take defaults: Tol = Epsilon, MaxIter = 10000, Count = 1
+// and a fixed pseudo-random start vector. Rayleigh quotient iteration
+// starts from the Rayleigh quotient of Start unless Shift is nonzero or
+// UseShift is set, the latter lets it start at shift 0.
+// Scratch vectors and factors come from Workspace, nil means a fresh one
+// per call
+type PowerOptions struct {
+	Shift     float64
+	UseShift  bool
+	Start     []float64
+	Tol       float64
+	MaxIter   int
+	Count     int
+	Workspace *Workspace
+}
+
+type EigenPair struct {
+	Value      float64
+	Vector     []float64
+	Iterations int
+	Residual   float64
+}
+
+func (o *PowerOptions) withDefaults(n int) (*PowerOptions, error) {
+	res := PowerOptions{}
+	if o != nil {
+		res = *o
+	}
+	if res.Tol <= 0 {
+		res.Tol = Epsilon
+	}
+	if res.MaxIter <= 0 {
+		res.MaxIter = powerMaxIter
+	}
+	if res.Count <= 0 {
+		res.Count = 1
+	}
+	if res.Count > n {
+		return nil, errors.New("more eigenpairs requested than matrix dim")
+	}
+	if res.Start != nil && len(res.Start) != n {
+		return nil, errors.New("matrix and start vector dims don't match")
+	}
+	if res.Workspace == nil {
+		res.Workspace = NewWorkspace()
+	}
+	return &res, nil
+}
+
+// x = Start, or the default start vector: fixed xorshift entries in
+// [0.5, 1.5)
+func (o *PowerOptions) startTo(x []float64) {
+	if o.Start != nil {
+		copy(x, o.Start)
+		return
+	}
+	s := uint64(0x9e3779b97f4a7c15)
+	for i := range x {
+		s ^= s << 13
+		s ^= s >> 7
+		s ^= s << 17
+		x[i] = float64(s >> 11) / (1 << 53) + 0.5
+	}
+}
+
+// the iterate is converged once ||Ax - λx|| <= Tol * max(1, |λ|)
+func eigenConverged(residual, lambda, tol float64) bool {
+	return residual <= tol * math.Max(1, math.Abs(lambda))
+}
+
+// Count eigenpairs of largest |λ - Shift| one after another, each further
+// one with the iterate kept orthogonal to the vectors already found,
+// which deflates them for symmetric matrices
+func PowerIteration(a LinearOperator, opts *PowerOptions) ([]EigenPair, error) {
+	return PowerIterationCtx(context.Background(), a, opts)
+}
+
+func PowerIterationCtx(ctx context.Context, a LinearOperator, opts *PowerOptions) ([]EigenPair, error) {
+	n, cols := a.Dims()
+	if n != cols || n == 0 {
+		return nil, errors.New("matrix isn't square or is empty")
+	}
+	o, err := opts.withDefaults(n)
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []EigenPair
+	for k := 0; k < o.Count; k++ {
+		x := make([]float64, n)
+		o.startTo(x)
+		orthogonalize(x, pairs)
+		if normalize(x) == 0 {
+			return pairs, errors.New("start vector lies in the deflated subspace")
+		}
+
+		pair, err := powerRun(ctx, a, x, o, pairs)
+		pairs = append(pairs, pair)
+		if err != nil {
+			return pairs, err
+		}
+	}
+
+	return pairs, nil
+}
+
+func powerRun(ctx context.Context, a LinearOperator, x []float64, o *PowerOptions, found []EigenPair) (EigenPair, error) {
+	n := len(x)
+	y, r := o.Workspace.vec(n), o.Workspace.vec(n)
+	defer releaseVecs(o.Workspace, y, r)
+	var lambda, residual float64
+
+	for iter := 1; iter <= o.MaxIter; iter++ {
+		a.MulVec(y, x)
+		lambda = dot(x, y)
+
+		residual = residualOf(y, x, lambda)
+
+		// found vectors are accurate only up to Tol, so later pairs are
+		// tested on the residual projected out of their span
+		for i := 0; i < n; i++ {
+			r[i] = y[i] - lambda * x[i]
+		}
+		orthogonalize(r, found)
+		if eigenConverged(VecNorm(r, EuclideanNorm), lambda, o.Tol) {
+			return EigenPair{lambda, x, iter, residual}, nil
+		}
+		if ctx.Err() != nil {
+			return EigenPair{lambda, x, iter, residual}, ctx.Err()
+		}
+
+		for i := 0; i < n; i++ {
+			x[i] = y[i] - o.Shift * x[i]
+		}
+		orthogonalize(x, found)
+		if normalize(x) == 0 {
+			// x was an eigenvector for λ = Shift
+			copy(x, y)
+			return EigenPair{lambda, x, iter, residual}, errors.New("iterate vanished: start vector is an eigenvector for the shift")
+		}
+	}
+
+	return EigenPair{lambda, x, o.MaxIter, residual}, errors.New("power iteration didn't converge")
+}
+
+// eigenpair with λ closest to Shift, (A - Shift * E) is factorized once
+func InverseIteration(a ElementAccessor, opts *PowerOptions) (*EigenPair, error) {
+	return InverseIterationCtx(context.Background(), a, opts)
+}
+
+func InverseIterationCtx(ctx context.Context, a ElementAccessor, opts *PowerOptions) (*EigenPair, error) {
+	n, cols := a.Dims()
+	if n != cols || n == 0 {
+		return nil, errors.New("matrix isn't square or is empty")
+	}
+	o, err := opts.withDefaults(n)
+	if err != nil {
+		return nil, err
+	}
+	ws := o.Workspace
+	m, own := denseIn(ws, a)
+	if own {
+		defer ws.releaseMat(m, false)
+	}
+
+	var f LUFactor
+	if err := factorizeShifted(&f, ws, m, o.Shift); err != nil {
+		return nil, err
+	}
+	defer f.Release()
+
+	x, z, ax := make([]float64, n), ws.vec(n), ws.vec(n)
+	defer releaseVecs(ws, z, ax)
+	o.startTo(x)
+	normalize(x)
+
+	var lambda, residual float64
+	for iter := 1; iter <= o.MaxIter; iter++ {
+		matVecTo(m, x, ax)
+		lambda = dot(x, ax)
+		residual = residualOf(ax, x, lambda)
+		if eigenConverged(residual, lambda, o.Tol) {
+			return &EigenPair{lambda, x, iter, residual}, nil
+		}
+		if ctx.Err() != nil {
+			return &EigenPair{lambda, x, iter, residual}, ctx.Err()
+		}
+
+		_ = f.SolveTo(z, x)
+		copy(x, z)
+		normalize(x)
+	}
+
+	return &EigenPair{lambda, x, o.MaxIter, residual}, errors.New("inverse iteration didn't converge")
+}
+
+// Shift, if set or UseShift, is used for the first step, then the shift
+// follows the Rayleigh quotient of the iterate; converges cubically for
+// symmetric matrices
+func RayleighQuotientIteration(a ElementAccessor, opts *PowerOptions) (*EigenPair, error) {
+	return RayleighQuotientIterationCtx(context.Background(), a, opts)
+}
+
+func RayleighQuotientIterationCtx(ctx context.Context, a ElementAccessor, opts *PowerOptions) (*EigenPair, error) {
+	n, cols := a.Dims()
+	if n != cols || n == 0 {
+		return nil, errors.New("matrix isn't square or is empty")
+	}
+	o, err := opts.withDefaults(n)
+	if err != nil {
+		return nil, err
+	}
+	ws := o.Workspace
+	m, own := denseIn(ws, a)
+	if own {
+		defer ws.releaseMat(m, false)
+	}
+
+	x, z, ax := make([]float64, n), ws.vec(n), ws.vec(n)
+	defer releaseVecs(ws, z, ax)
+	o.startTo(x)
+	normalize(x)
+
+	shift := o.Shift
+	if shift == 0 && !o.UseShift {
+		matVecTo(m, x, ax)
+		shift = dot(x, ax)
+	}
+
+	var f LUFactor
+	var lambda, residual float64
+	for iter := 1; iter <= o.MaxIter; iter++ {
+		matVecTo(m, x, ax)
+		lambda = dot(x, ax)
+		residual = residualOf(ax, x, lambda)
+		if eigenConverged(residual, lambda, o.Tol) {
+			return &EigenPair{lambda, x, iter, residual}, nil
+		}
+		if ctx.Err() != nil {
+			return &EigenPair{lambda, x, iter, residual}, ctx.Err()
+		}
+		if iter > 1 {
+			shift = lambda
+		}
+
+		if err := factorizeShifted(&f, ws, m, shift); err != nil {
+			return &EigenPair{lambda, x, iter, residual}, err
+		}
+		_ = f.SolveTo(z, x)
+		f.Release()
+		copy(x, z)
+		normalize(x)
+	}
+
+	return &EigenPair{lambda, x, o.MaxIter, residual}, errors.New("rayleigh quotient iteration didn't converge")
+}
+
+// f = LU of (A - shift * E) in storage from ws; a shift right on an
+// eigenvalue is moved off slightly
+func factorizeShifted(f *LUFactor, ws *Workspace, a *Matrix, shift float64) error {
+	shifted := ws.mat(a.rows, a.cols)
+	defer ws.releaseMat(shifted, false)
+
+	shiftTo(shifted, a, shift)
+	if err := f.factorize(shifted, ws); err == nil {
+		return nil
+	}
+	shiftTo(shifted, a, shift + math.Max(1, math.Abs(shift)) * 1e-10)
+	return f.factorize(shifted, ws)
+}
+
+// dst = a - shift * E
+func shiftTo(dst, a *Matrix, shift float64) {
+	_ = dst.CopyFrom(a)
+	for i := 0; i < a.rows; i++ {
+		dst.data[i * dst.stride + i] -= shift
+	}
+}
+
+func matVecTo(a *Matrix, x, dst []float64) {
+	for i := 0; i < a.rows; i++ {
+		var sum float64
+		for j, aij := range a.row(i) {
+			sum += aij * x[j]
+		}
+		dst[i] = sum
+	}
+}
+
+func dot(a, b []float64) float64 {
+	var res float64
+	for i, ai := range a {
+		res += ai * b[i]
+	}
+	return res
+}
+
+func residualOf(ax, x []float64, lambda float64) float64 {
+	var res float64
+	for i := range x {
+		r := ax[i] - lambda * x[i]
+		res += r * r
+	}
+	return math.Sqrt(res)
+}
+
+func normalize(x []float64) float64 {
+	norm := VecNorm(x, EuclideanNorm)
+	if norm == 0 {
+		return 0
+	}
+	for i := range x {
+		x[i] /= norm
+	}
+	return norm
+}
+
+func orthogonalize(x []float64, pairs []EigenPair) {
+	for _, p := range pairs {
+		c := dot(x, p.Vector)
+		for i := range x {
+			x[i] -= c * p.Vector[i]
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package algnum
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func tridiagData(n int) [][]float64 {
+	data := init2dSlice(n, n)
+	for i := 0; i < n; i++ {
+		data[i][i] = 2
+		if i > 0 {
+			data[i][i - 1] = -1
+			data[i - 1][i] = -1
+		}
+	}
+	return data
+}
+
+func checkEigenPair(t *testing.T, a *Matrix, p EigenPair, expected, tol float64) {
+	checkEigenPairResidual(t, a, p, expected, tol, 1e-6)
+}
+
+func checkEigenPairResidual(t *testing.T, a *Matrix, p EigenPair, expected, tol, resTol float64) {
+	if math.Abs(p.Value - expected) > tol {
+		t.Fatalf("result is wrong: expected %0.15f, got %0.15f", expected, p.Value)
+	}
+	ax := make([]float64, a.rows)
+	matVecTo(a, p.Vector, ax)
+	if r := residualOf(ax, p.Vector, p.Value); math.Abs(r - p.Residual) > 1e-12 || r > resTol * math.Max(1, math.Abs(p.Value)) {
+		t.Fatalf("residual is wrong: reported %e, actual %e", p.Residual, r)
+	}
+}
+
+func TestPowerIteration(t *testing.T) {
+	n := 10
+	data := tridiagData(n)
+	a, _ := InitMat(data)
+	lapack := lapackEigenSym(data, n)
+
+	pairs, err := PowerIteration(a, &PowerOptions{Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(pairs) != 3 {
+		t.Fatalf("expected 3 eigenpairs, got %d", len(pairs))
+	}
+	for k, p := range pairs {
+		checkEigenPairResidual(t, a, p, lapack[n - 1 - k], 1e-9, 1e-5)
+		if p.Iterations <= 0 {
+			t.Fatal("iteration count isn't reported")
+		}
+	}
+
+	// shifted by the largest eigenvalue, the smallest one becomes dominant
+	shifted, err := PowerIteration(a, &PowerOptions{Shift: lapack[n - 1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEigenPair(t, a, shifted[0], lapack[0], 1e-9)
+
+	_, err = PowerIteration(a, &PowerOptions{MaxIter: 2})
+	if err == nil {
+		t.Fatal("expected not converged error")
+	}
+
+	t.Log("power iteration works correct")
+}
+
+func TestInverseIteration(t *testing.T) {
+	n := 10
+	data := tridiagData(n)
+	a, _ := InitMat(data)
+	lapack := lapackEigenSym(data, n)
+
+	p, err := InverseIteration(a, &PowerOptions{Shift: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closest := lapack[0]
+	for _, l := range lapack {
+		if math.Abs(l - 1) < math.Abs(closest - 1) {
+			closest = l
+		}
+	}
+	checkEigenPair(t, a, *p, closest, 1e-9)
+
+	// shift exactly on an eigenvalue
+	diag, _ := InitMat([][]float64{
+		{1, 0},
+		{0, 3},
+	})
+	p, err = InverseIteration(diag, &PowerOptions{Shift: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEigenPair(t, diag, *p, 3, 1e-9)
+
+	t.Log("inverse iteration works correct")
+}
+
+func TestRayleighQuotientIteration(t *testing.T) {
+	n := 30
+	data := randSymData(n, n, -10, 10)
+	a, _ := InitMat(data)
+	lapack := lapackEigenSym(data, n)
+
+	p, err := RayleighQuotientIteration(a, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, l := range lapack {
+		if math.Abs(l - p.Value) <= 1e-8 * math.Max(1, math.Abs(l)) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("result is wrong: %0.15f isn't an eigenvalue of\n %s", p.Value, VectToStr(lapack))
+	}
+	checkEigenPair(t, a, *p, p.Value, 0)
+	if p.Iterations > 20 {
+		t.Fatalf("expected fast convergence, got %d iterations", p.Iterations)
+	}
+
+	// the first step at shift 0 finds the eigenvalue closest to 0, the
+	// Rayleigh quotient of the start vector heads for another one
+	diag, _ := InitMat([][]float64{
+		{0.1, 0, 0},
+		{0, 5, 0},
+		{0, 0, 9},
+	})
+	start := []float64{0.3, 1, 1}
+	p, err = RayleighQuotientIteration(diag, &PowerOptions{Start: start, UseShift: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEigenPair(t, diag, *p, 0.1, 1e-9)
+	p, err = RayleighQuotientIteration(diag, &PowerOptions{Start: start})
+	if err != nil {
+		t.Fatal(err)
+	} else if math.Abs(p.Value - 0.1) < 1e-6 {
+		t.Fatal("rayleigh quotient start converged to the eigenvalue of shift 0")
+	}
+
+	t.Log("rayleigh quotient iteration works correct")
+}
+
+func TestMyMinMaxEigenvaluesAccuracy(t *testing.T) {
+	data := [][]float64{
+		{7, 0.5},
+		{0.5, 1},
+	}
+	a, _ := InitMat(data)
+
+	min, max, err := MyMinMaxEigenvalues(a)
+	if err != nil {
+		t.Fatal(err)
+	} else if math.Abs(min - (4 - math.Sqrt(9.25))) > 1e-6 || math.Abs(max - (4 + math.Sqrt(9.25))) > 1e-6 {
+		t.Fatalf("result is wrong: expected (%0.15f, %0.15f), got (%0.15f, %0.15f)",
+			4 - math.Sqrt(9.25), 4 + math.Sqrt(9.25), min, max)
+	} else {
+		t.Log("min max eigenvalues work correct")
+	}
+}
+
+func TestPowerIterationCtx(t *testing.T) {
+	a, _ := InitMat(tridiagData(10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	pairs, err := PowerIterationCtx(ctx, a, nil)
+	if err != context.Canceled || len(pairs) != 1 || pairs[0].Iterations != 1 {
+		t.Fatalf("expected canceled error with partial result, got %v", err)
+	}
+	if p, err := InverseIterationCtx(ctx, a, &PowerOptions{Shift: 1}); err != context.Canceled || p == nil {
+		t.Fatalf("expected canceled error with partial result, got %v", err)
+	}
+	if p, err := RayleighQuotientIterationCtx(ctx, a, nil); err != context.Canceled || p == nil {
+		t.Fatalf("expected canceled error with partial result, got %v", err)
+	} else {
+		t.Log("power iteration cancellation works correct")
+	}
+}
@@ -0,0 +1,173 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+)
+
+// approximates A^(-1): dst = M^(-1) * r, dst must not alias r
+type Preconditioner interface {
+	Apply(dst, r []float64)
+}
+
+type JacobiPreconditioner struct {
+	invDiag []float64
+}
+
+type SSORPreconditioner struct {
+	a     *CSR
+	diag  []float64
+	omega float64
+	work  []float64
+}
+
+// zero fill-in incomplete Cholesky, L keeps the sparsity of the lower
+// triangle of A; its rows are sorted, so the diagonal comes last
+type ICPreconditioner struct {
+	l *CSR
+}
+
+func InitJacobiPreconditioner(a ElementAccessor) (*JacobiPreconditioner, error) {
+	n, cols := a.Dims()
+	if n != cols {
+		return nil, errors.New("matrix isn't square")
+	}
+	invDiag := make([]float64, n)
+	for i := 0; i < n; i++ {
+		aii := a.At(i, i)
+		if aii == 0 {
+			return nil, errors.New("matrix has zero diagonal element")
+		}
+		invDiag[i] = 1 / aii
+	}
+	return &JacobiPreconditioner{invDiag}, nil
+}
+
+func (p *JacobiPreconditioner) Apply(dst, r []float64) {
+	for i, d := range p.invDiag {
+		dst[i] = d * r[i]
+	}
+}
+
+// M = (D + ωL) D^(-1) (D + ωU) / (ω (2 - ω)), symmetric for symmetric A;
+// a is kept in CSR, so an application costs O(nnz)
+func InitSSORPreconditioner(a ElementAccessor, omega float64) (*SSORPreconditioner, error) {
+	n, cols := a.Dims()
+	if n != cols {
+		return nil, errors.New("matrix isn't square")
+	}
+	if omega <= 0 || omega >= 2 {
+		return nil, errors.New("relaxation factor isn't in (0, 2)")
+	}
+	s := csrOf(a)
+	diag := make([]float64, n)
+	for i := 0; i < n; i++ {
+		diag[i] = s.At(i, i)
+		if diag[i] == 0 {
+			return nil, errors.New("matrix has zero diagonal element")
+		}
+	}
+	return &SSORPreconditioner{s, diag, omega, make([]float64, n)}, nil
+}
+
+func (p *SSORPreconditioner) Apply(dst, r []float64) {
+	n, w, y := len(p.diag), p.omega, p.work
+
+	for i := 0; i < n; i++ {
+		ind, vals := p.a.Row(i)
+		sum := r[i]
+		for k := 0; k < len(ind) && ind[k] < i; k++ {
+			sum -= w * vals[k] * y[ind[k]]
+		}
+		y[i] = sum / p.diag[i]
+	}
+	for i := n - 1; i >= 0; i-- {
+		ind, vals := p.a.Row(i)
+		sum := y[i] * p.diag[i]
+		for k := len(ind) - 1; k >= 0 && ind[k] > i; k-- {
+			sum -= w * vals[k] * dst[ind[k]]
+		}
+		dst[i] = sum / p.diag[i]
+	}
+	for i := 0; i < n; i++ {
+		dst[i] *= w * (2 - w)
+	}
+}
+
+// L is computed row by row, l[i][j] = (a[i][j] - Σ l[i][k] l[j][k]) / l[j][j]
+// with k running over the columns stored in both rows, so the cost
+// depends on the nonzeros only
+func InitICPreconditioner(a ElementAccessor) (*ICPreconditioner, error) {
+	s := csrOf(a)
+	if !s.IsSymmetric() {
+		return nil, errors.New("matrix isn't symmetric")
+	}
+	n := s.rows
+
+	l := &CSR{rows: n, cols: n, rowPtr: make([]int, n + 1)}
+	for i := 0; i < n; i++ {
+		ind, vals := s.Row(i)
+		for k := 0; k < len(ind) && ind[k] <= i; k++ {
+			l.colInd = append(l.colInd, ind[k])
+			l.vals = append(l.vals, vals[k])
+		}
+		if len(l.colInd) == l.rowPtr[i] || l.colInd[len(l.colInd) - 1] != i {
+			return nil, errors.New("incomplete cholesky breakdown")
+		}
+		l.rowPtr[i + 1] = len(l.vals)
+	}
+
+	for i := 0; i < n; i++ {
+		start, end := l.rowPtr[i], l.rowPtr[i + 1]
+		for p := start; p < end; p++ {
+			j, sum := l.colInd[p], l.vals[p]
+			// row j without its diagonal, merged with the part of row i before p
+			q, qEnd := l.rowPtr[j], l.rowPtr[j + 1] - 1
+			for r := start; r < p && q < qEnd; {
+				switch ci, cj := l.colInd[r], l.colInd[q]; {
+				case ci == cj:
+					sum -= l.vals[r] * l.vals[q]
+					r++
+					q++
+				case ci < cj:
+					r++
+				default:
+					q++
+				}
+			}
+
+			if j < i {
+				l.vals[p] = sum / l.vals[qEnd]
+			} else if sum <= 0 {
+				return nil, errors.New("incomplete cholesky breakdown")
+			} else {
+				l.vals[p] = math.Sqrt(sum)
+			}
+		}
+	}
+
+	return &ICPreconditioner{l}, nil
+}
+
+func (p *ICPreconditioner) Apply(dst, r []float64) {
+	l := p.l
+
+	for i := 0; i < l.rows; i++ {
+		ind, vals := l.Row(i)
+		last := len(ind) - 1
+		sum := r[i]
+		for k := 0; k < last; k++ {
+			sum -= vals[k] * dst[ind[k]]
+		}
+		dst[i] = sum / vals[last]
+	}
+	// Lᵀ by columns: once dst[i] is final it is taken out of the rows above
+	for i := l.rows - 1; i >= 0; i-- {
+		ind, vals := l.Row(i)
+		last := len(ind) - 1
+		dst[i] /= vals[last]
+		for k := 0; k < last; k++ {
+			dst[ind[k]] -= vals[k] * dst[i]
+		}
+	}
+}
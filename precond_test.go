@@ -0,0 +1,121 @@
+package algnum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPreconditioners(t *testing.T) {
+	data := randDiagSym(30, 30, 1, 10)
+	a, _ := InitMat(data)
+	n := a.rows
+	r := randFree(n, 1, 10)
+	z, mz := make([]float64, n), make([]float64, n)
+
+	// a dense matrix has no zero pattern, so IC(0) is the full Cholesky
+	ic, err := InitICPreconditioner(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ic.Apply(z, r)
+	a.MulVec(mz, z)
+	if !VectsEq(r, mz, 1e-9) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(r), VectToStr(mz))
+	}
+
+	jacobi, err := InitJacobiPreconditioner(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jacobi.Apply(z, r)
+	for i := 0; i < n; i++ {
+		mz[i] = a.data[i * a.stride + i] * z[i]
+	}
+	if !VectsEq(r, mz, 1e-9) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(r), VectToStr(mz))
+	}
+
+	omega := 1.3
+	ssor, err := InitSSORPreconditioner(a, omega)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lower, upper, diagInv := init2dSlice(n, n), init2dSlice(n, n), init2dSlice(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			switch {
+			case j < i:
+				lower[i][j] = omega * a.data[i * a.stride + j]
+			case j > i:
+				upper[i][j] = omega * a.data[i * a.stride + j]
+			default:
+				lower[i][j], upper[i][j] = a.data[i * a.stride + i], a.data[i * a.stride + i]
+				diagInv[i][j] = 1 / (omega * (2 - omega) * a.data[i * a.stride + i])
+			}
+		}
+	}
+	lMat, _ := InitMat(lower)
+	uMat, _ := InitMat(upper)
+	dMat, _ := InitMat(diagInv)
+	ld, _ := MatMul(lMat, dMat)
+	m, _ := MatMul(ld, uMat)
+
+	ssor.Apply(z, r)
+	m.MulVec(mz, z)
+	if !VectsEq(r, mz, 1e-9) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(r), VectToStr(mz))
+	}
+
+	if _, err := InitSSORPreconditioner(a, 2); err == nil {
+		t.Fatal("expected relaxation factor error")
+	} else {
+		t.Log("preconditioners work correct")
+	}
+}
+
+func TestSparsePreconditioners(t *testing.T) {
+	k := 8
+	n := k * k
+	dense, _ := InitMat(laplacian2dData(k))
+	a := MatToCSR(dense)
+	r := randFree(n, 1, 10)
+
+	// IC(0) reproduces a on its own pattern: (L Lᵀ)[i][j] = a[i][j] where a[i][j] != 0
+	ic, err := InitICPreconditioner(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ic.l.NNZ() != (a.NNZ() + n) / 2 {
+		t.Fatalf("L has %d nonzeros, the lower triangle of a %d", ic.l.NNZ(), (a.NNZ() + n) / 2)
+	}
+	l := ic.l.ToMat()
+	llt, _ := MatMul(l, TransposeMat(l))
+	for i := 0; i < n; i++ {
+		ind, vals := a.Row(i)
+		for p, j := range ind {
+			if math.Abs(llt.At(i, j) - vals[p]) > 1e-12 {
+				t.Fatalf("L Lᵀ is %f at %d, %d, expected %f", llt.At(i, j), i, j, vals[p])
+			}
+		}
+	}
+
+	// applying it solves L Lᵀ z = r
+	z, mz := make([]float64, n), make([]float64, n)
+	ic.Apply(z, r)
+	llt.MulVec(mz, z)
+	if !VectsEq(r, mz, 1e-9) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(r), VectToStr(mz))
+	}
+
+	// sparse and dense inputs give the same SSOR
+	sparse, _ := InitSSORPreconditioner(a, 1.2)
+	full, _ := InitSSORPreconditioner(dense, 1.2)
+	zDense := make([]float64, n)
+	sparse.Apply(z, r)
+	full.Apply(zDense, r)
+	if !VectsEq(zDense, z, 1e-12) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(zDense), VectToStr(z))
+	} else {
+		t.Log("sparse preconditioners work correct")
+	}
+}
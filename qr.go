@@ -0,0 +1,295 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+)
+
+type givensRot struct {
+	i, k int
+	c, s float64
+}
+
+func QR(m *Matrix, pivoting bool) (*QRFactor, error) {
+	if m.rows == 0 {
+		return nil, errors.New("matrix is empty")
+	}
+
+	var qr [][]float64
+	var tau []float64
+	var perm Permutation
+	if pivoting {
+		qr, tau, perm = householderQRPivot(m.rowSlices())
+	} else {
+		qr, tau = householderQR(m.rowSlices())
+	}
+
+	return newQRFactor(qr, tau, nil, perm, m.rows, m.cols), nil
+}
+
+// Givens rotations only touch the nonzero subdiagonal entries, so banded
+// and sparse matrices are factorized in far fewer operations
+func GivensQR(m *Matrix) (*QRFactor, error) {
+	if m.rows == 0 {
+		return nil, errors.New("matrix is empty")
+	}
+
+	r := copy2dSlice(m.rowSlices())
+	var rots []givensRot
+	for j := 0; j < intMin(m.rows - 1, m.cols); j++ {
+		for i := j + 1; i < m.rows; i++ {
+			if r[i][j] == 0 {
+				continue
+			}
+			a, b := r[j][j], r[i][j]
+			h := math.Hypot(a, b)
+			c, s := a / h, b / h
+			for k := j; k < m.cols; k++ {
+				rj, ri := r[j][k], r[i][k]
+				r[j][k] = c * rj + s * ri
+				r[i][k] = -s * rj + c * ri
+			}
+			r[i][j] = 0
+			rots = append(rots, givensRot{j, i, c, s})
+		}
+	}
+
+	return newQRFactor(r, nil, rots, nil, m.rows, m.cols), nil
+}
+
+func newQRFactor(qr [][]float64, tau []float64, rots []givensRot, perm Permutation, rows, cols int) *QRFactor {
+	k := intMin(rows, cols)
+	tol := float64(intMax(rows, cols)) * machEps * maxAbsUpper(qr)
+
+	rank := 0
+	for i := 0; i < k; i++ {
+		if math.Abs(qr[i][i]) > tol {
+			rank++
+		} else if perm != nil {
+			break
+		}
+	}
+
+	return &QRFactor{
+		qr: qr, tau: tau, rots: rots, perm: perm, rank: rank,
+		rows: rows, cols: cols, work: make([]float64, rows + cols),
+	}
+}
+
+func (f *QRFactor) Rank() int {
+	return f.rank
+}
+
+// column permutation of AP = QR, identity without pivoting
+func (f *QRFactor) Perm() Permutation {
+	if f.perm == nil {
+		return IdentityPerm(f.cols)
+	}
+	res := make(Permutation, f.cols)
+	copy(res, f.perm)
+	return res
+}
+
+func (f *QRFactor) Q() *Matrix {
+	return f.formQ(f.rows)
+}
+
+func (f *QRFactor) ThinQ() *Matrix {
+	return f.formQ(intMin(f.rows, f.cols))
+}
+
+func (f *QRFactor) R() *Matrix {
+	return f.formR(f.rows)
+}
+
+func (f *QRFactor) ThinR() *Matrix {
+	return f.formR(intMin(f.rows, f.cols))
+}
+
+func (f *QRFactor) formQ(cols int) *Matrix {
+	res, _ := InitMatOfDims(f.rows, cols)
+	e := make([]float64, f.rows)
+	for j := 0; j < cols; j++ {
+		for i := range e {
+			e[i] = 0
+		}
+		e[j] = 1
+		f.applyQ(e)
+		for i := 0; i < f.rows; i++ {
+			res.data[i * res.stride + j] = e[i]
+		}
+	}
+	return res
+}
+
+func (f *QRFactor) formR(rows int) *Matrix {
+	res, _ := InitMatOfDims(rows, f.cols)
+	for i := 0; i < intMin(rows, f.rows); i++ {
+		for j := i; j < f.cols; j++ {
+			res.data[i * res.stride + j] = f.qr[i][j]
+		}
+	}
+	return res
+}
+
+func (f *QRFactor) applyQT(b []float64) {
+	if f.rots == nil {
+		applyQT(f.qr, f.tau, b)
+		return
+	}
+	for _, r := range f.rots {
+		bi, bk := b[r.i], b[r.k]
+		b[r.i] = r.c * bi + r.s * bk
+		b[r.k] = -r.s * bi + r.c * bk
+	}
+}
+
+func (f *QRFactor) applyQ(b []float64) {
+	if f.rots == nil {
+		applyQ(f.qr, f.tau, b)
+		return
+	}
+	for l := len(f.rots) - 1; l >= 0; l-- {
+		r := f.rots[l]
+		bi, bk := b[r.i], b[r.k]
+		b[r.i] = r.c * bi - r.s * bk
+		b[r.k] = r.s * bi + r.c * bk
+	}
+}
+
+// Householder QR done in place on a copy of data: R is stored in the upper
+// triangle, the reflector vectors v_k (with implicit v_k[k] = 1) below it,
+// H_k = I - tau[k] * v_k * v_k^T and Q = H_0 * H_1 * ... * H_{n-1}
+func householderQR(data [][]float64) ([][]float64, []float64) {
+	qr := copy2dSlice(data)
+	m := len(qr)
+	var n int
+	if m > 0 {
+		n = len(qr[0])
+	}
+	k := intMin(m, n)
+	tau := make([]float64, k)
+
+	for j := 0; j < k; j++ {
+		tau[j] = householderCol(qr, j, j)
+		applyHouseholderLeft(qr, j, j, tau[j], j + 1)
+	}
+
+	return qr, tau
+}
+
+// at every step the remaining column of largest norm is moved forward,
+// so |R[i][i]| doesn't increase and trailing negligible pivots give the rank
+func householderQRPivot(data [][]float64) ([][]float64, []float64, Permutation) {
+	qr := copy2dSlice(data)
+	m := len(qr)
+	n := len(qr[0])
+	k := intMin(m, n)
+	tau := make([]float64, k)
+	perm := IdentityPerm(n)
+
+	for j := 0; j < k; j++ {
+		p, max := j, float64(-1)
+		for c := j; c < n; c++ {
+			var norm float64
+			for i := j; i < m; i++ {
+				norm += qr[i][c] * qr[i][c]
+			}
+			if norm > max {
+				max = norm
+				p = c
+			}
+		}
+		if p != j {
+			for i := 0; i < m; i++ {
+				qr[i][p], qr[i][j] = qr[i][j], qr[i][p]
+			}
+			perm[p], perm[j] = perm[j], perm[p]
+		}
+
+		tau[j] = householderCol(qr, j, j)
+		applyHouseholderLeft(qr, j, j, tau[j], j + 1)
+	}
+
+	return qr, tau, perm
+}
+
+// builds the reflector annihilating qr[row+1:][col], returns its tau
+func householderCol(qr [][]float64, row, col int) float64 {
+	m := len(qr)
+
+	var sigma float64
+	for i := row + 1; i < m; i++ {
+		sigma += qr[i][col] * qr[i][col]
+	}
+	if sigma == 0 {
+		return 0
+	}
+
+	alpha := qr[row][col]
+	beta := -math.Copysign(math.Sqrt(alpha * alpha + sigma), alpha)
+	for i := row + 1; i < m; i++ {
+		qr[i][col] /= alpha - beta
+	}
+	qr[row][col] = beta
+
+	return (beta - alpha) / beta
+}
+
+// applies H = I - tau * v * v^T with v stored in qr[row:][col] to the
+// columns from fromCol on
+func applyHouseholderLeft(qr [][]float64, row, col int, tau float64, fromCol int) {
+	if tau == 0 {
+		return
+	}
+	m := len(qr)
+	for j := fromCol; j < len(qr[row]); j++ {
+		s := qr[row][j]
+		for i := row + 1; i < m; i++ {
+			s += qr[i][col] * qr[i][j]
+		}
+		s *= tau
+		qr[row][j] -= s
+		for i := row + 1; i < m; i++ {
+			qr[i][j] -= s * qr[i][col]
+		}
+	}
+}
+
+// b = Q^T * b
+func applyQT(qr [][]float64, tau []float64, b []float64) {
+	m := len(qr)
+	for k := range tau {
+		if tau[k] == 0 {
+			continue
+		}
+		s := b[k]
+		for i := k + 1; i < m; i++ {
+			s += qr[i][k] * b[i]
+		}
+		s *= tau[k]
+		b[k] -= s
+		for i := k + 1; i < m; i++ {
+			b[i] -= s * qr[i][k]
+		}
+	}
+}
+
+// b = Q * b
+func applyQ(qr [][]float64, tau []float64, b []float64) {
+	m := len(qr)
+	for k := len(tau) - 1; k >= 0; k-- {
+		if tau[k] == 0 {
+			continue
+		}
+		s := b[k]
+		for i := k + 1; i < m; i++ {
+			s += qr[i][k] * b[i]
+		}
+		s *= tau[k]
+		b[k] -= s
+		for i := k + 1; i < m; i++ {
+			b[i] -= s * qr[i][k]
+		}
+	}
+}
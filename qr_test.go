@@ -0,0 +1,187 @@
+package algnum
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"testing"
+)
+
+func lapackQRDiag(m [][]float64, rows, cols int) []float64 {
+	var mVec []float64
+	for i := 0; i < rows; i++ {
+		mVec = append(mVec, m[i]...)
+	}
+	gonumA := mat.NewDense(rows, cols, mVec)
+
+	var qr mat.QR
+	qr.Factorize(gonumA)
+	var r mat.Dense
+	qr.RTo(&r)
+
+	res := make([]float64, intMin(rows, cols))
+	for i := range res {
+		res[i] = r.At(i, i)
+	}
+	return res
+}
+
+func checkQR(t *testing.T, a *Matrix, f *QRFactor, eps float64) {
+	q, r := f.Q(), f.R()
+	e, _ := IdentityMat(a.rows)
+	qtq, _ := MatMul(TransposeMat(q), q)
+	if !MatsEq(qtq, e, eps) {
+		t.Fatalf("Q isn't orthogonal:\n %s", qtq.ToStr())
+	}
+
+	ap, _ := InitMatOfDims(a.rows, a.cols)
+	for j, pj := range f.Perm() {
+		for i := 0; i < a.rows; i++ {
+			ap.data[i * ap.stride + j] = a.data[i * a.stride + pj]
+		}
+	}
+
+	qrMat, _ := MatMul(q, r)
+	if !MatsEq(qrMat, ap, eps) {
+		sub, _ := MatsSub(qrMat, ap)
+		t.Fatalf("result is wrong: QR - AP norm %0.15f", sub.Norm(EuclideanNorm))
+	}
+
+	thin, _ := MatMul(f.ThinQ(), f.ThinR())
+	if !MatsEq(thin, ap, eps) {
+		sub, _ := MatsSub(thin, ap)
+		t.Fatalf("result is wrong: thin QR - AP norm %0.15f", sub.Norm(EuclideanNorm))
+	}
+}
+
+func TestQR(t *testing.T) {
+	data := make([][]float64, 30)
+	for i := range data {
+		data[i] = randFree(20, 1, 10)
+	}
+	a, _ := InitMat(data)
+
+	f, err := QR(a, false)
+	if err != nil {
+		t.Fatal(err)
+	} else if f.Rank() != 20 {
+		t.Fatalf("rank is wrong: expected %d, got %d", 20, f.Rank())
+	}
+	checkQR(t, a, f, 1e-9)
+
+	lapackDiag := lapackQRDiag(data, 30, 20)
+	r := f.ThinR()
+	for i, d := range lapackDiag {
+		if math.Abs(math.Abs(r.data[i * r.stride + i]) - math.Abs(d)) > 1e-9 * math.Abs(d) {
+			t.Fatalf("R is wrong: expected |r%d%d| = %0.15f, got %0.15f", i, i, math.Abs(d), math.Abs(r.data[i * r.stride + i]))
+		}
+	}
+
+	fp, err := QR(a, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQR(t, a, fp, 1e-9)
+
+	wideData := make([][]float64, 10)
+	for i := range wideData {
+		wideData[i] = randFree(20, 1, 10)
+	}
+	wide, _ := InitMat(wideData)
+	fw, err := QR(wide, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQR(t, wide, fw, 1e-9)
+	t.Log("QR works correct")
+}
+
+func TestQRRank(t *testing.T) {
+	data := [][]float64{
+		{1, 2, 3, 1},
+		{4, 5, 9, 0},
+		{7, 8, 15, 2},
+		{1, 0, 1, 5},
+		{2, 2, 4, 1},
+		{3, 1, 4, 7},
+	}
+	a, _ := InitMat(data)
+
+	f, err := QR(a, true)
+	if err != nil {
+		t.Fatal(err)
+	} else if f.Rank() != 3 {
+		t.Fatalf("rank is wrong: expected %d, got %d", 3, f.Rank())
+	}
+	checkQR(t, a, f, 1e-9)
+
+	b := []float64{7, 18, 32, 7, 9, 15}
+	x, err := f.Solve(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xMat, _ := InitMatOfDims(4, 1)
+	for i, xi := range x {
+		xMat.data[i * xMat.stride + 0] = xi
+	}
+	check, _ := MatMul(a, xMat)
+	for i := range b {
+		if math.Abs(check.data[i * check.stride + 0] - b[i]) > 1e-8 {
+			t.Fatalf("basic solution is wrong: A * x =\n %s", check.ToStr())
+		}
+	}
+
+	if _, err := FactorizeQR(a); err == nil {
+		t.Fatal("expected singular matrix error")
+	} else {
+		t.Log("QR rank works correct")
+	}
+}
+
+func TestGivensQR(t *testing.T) {
+	n := 50
+	a, _ := InitMatOfDim(n)
+	for i := 0; i < n; i++ {
+		a.data[i * a.stride + i] = 4
+		if i > 0 {
+			a.data[i * a.stride + i - 1] = -1
+			a.data[(i - 1) * a.stride + i] = -1
+		}
+	}
+
+	f, err := GivensQR(a)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(f.rots) != n - 1 {
+		t.Fatalf("rotations count is wrong: expected %d, got %d", n - 1, len(f.rots))
+	}
+	checkQR(t, a, f, 1e-9)
+
+	dataN := randData(40, 40, 1, 10)
+	matN, _ := InitMat(dataN)
+	fN := randFree(40, 1, 10)
+
+	g, err := GivensQR(matN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQR(t, matN, g, 1e-9)
+
+	lapack, err := lapackSolve(dataN, len(dataN), fN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.Solve(fN)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(res, lapack, Epsilon) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(res))
+	}
+
+	d, _ := g.Det()
+	lapackD := lapackDet(dataN, len(dataN))
+	if math.Abs(d - lapackD) > 1e-8 * math.Abs(lapackD) {
+		t.Fatalf("det is wrong: expected %e, got %e", lapackD, d)
+	} else {
+		t.Log("givens QR works correct")
+	}
+}
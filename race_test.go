@@ -0,0 +1,6 @@
+// +build race
+
+package algnum
+
+// the race detector drops pooled items at random
+const raceEnabled = true
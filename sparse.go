@@ -0,0 +1,462 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// compressed sparse row storage: the entries of row i are
+// vals[rowPtr[i] : rowPtr[i + 1]] in columns colInd[rowPtr[i] : rowPtr[i + 1]],
+// sorted by column
+type CSR struct {
+	rows, cols int
+	rowPtr     []int
+	colInd     []int
+	vals       []float64
+}
+
+// compressed sparse column storage, the transposed layout of CSR
+type CSC struct {
+	rows, cols int
+	colPtr     []int
+	rowInd     []int
+	vals       []float64
+}
+
+// coordinate (triplet) builder, duplicate entries are summed on conversion
+type COO struct {
+	rows, cols int
+	rowInd     []int
+	colInd     []int
+	vals       []float64
+}
+
+func InitCOO(rows, cols int) (*COO, error) {
+	if rows < 0 || cols < 0 {
+		return nil, errors.New("matrix dims are negative")
+	}
+	return &COO{rows: rows, cols: cols}, nil
+}
+
+func (c *COO) Append(i, j int, v float64) error {
+	if i < 0 || i >= c.rows || j < 0 || j >= c.cols {
+		return errors.New("index out of range")
+	}
+	c.rowInd = append(c.rowInd, i)
+	c.colInd = append(c.colInd, j)
+	c.vals = append(c.vals, v)
+	return nil
+}
+
+func (c *COO) Dims() (int, int) {
+	return c.rows, c.cols
+}
+
+func (c *COO) ToCSR() *CSR {
+	ptr, ind, vals := compress(c.rows, c.rowInd, c.colInd, c.vals)
+	return &CSR{c.rows, c.cols, ptr, ind, vals}
+}
+
+func (c *COO) ToCSC() *CSC {
+	ptr, ind, vals := compress(c.cols, c.colInd, c.rowInd, c.vals)
+	return &CSC{c.rows, c.cols, ptr, ind, vals}
+}
+
+// counting sort of the triplets by major index, then every major slice is
+// sorted by minor index and duplicates are summed; explicit zeros are dropped
+func compress(n int, major, minor []int, vals []float64) ([]int, []int, []float64) {
+	ptr := make([]int, n + 1)
+	for _, i := range major {
+		ptr[i + 1]++
+	}
+	for i := 0; i < n; i++ {
+		ptr[i + 1] += ptr[i]
+	}
+
+	next := make([]int, n)
+	copy(next, ptr)
+	ind, v := make([]int, len(major)), make([]float64, len(major))
+	for k, i := range major {
+		ind[next[i]], v[next[i]] = minor[k], vals[k]
+		next[i]++
+	}
+
+	resPtr := make([]int, n + 1)
+	resInd, resVals := ind[ : 0], v[ : 0]
+	for i := 0; i < n; i++ {
+		sort.Sort(&byIndex{ind[ptr[i] : ptr[i + 1]], v[ptr[i] : ptr[i + 1]]})
+		for k := ptr[i]; k < ptr[i + 1]; k++ {
+			if last := len(resInd) - 1; last >= resPtr[i] && resInd[last] == ind[k] {
+				resVals[last] += v[k]
+			} else {
+				resInd, resVals = append(resInd, ind[k]), append(resVals, v[k])
+			}
+		}
+		// compact explicit and cancelled zeros
+		nnz := resPtr[i]
+		for k := resPtr[i]; k < len(resInd); k++ {
+			if resVals[k] != 0 {
+				resInd[nnz], resVals[nnz] = resInd[k], resVals[k]
+				nnz++
+			}
+		}
+		resInd, resVals = resInd[ : nnz], resVals[ : nnz]
+		resPtr[i + 1] = nnz
+	}
+
+	return resPtr, resInd, resVals
+}
+
+type byIndex struct {
+	ind  []int
+	vals []float64
+}
+
+func (s *byIndex) Len() int {
+	return len(s.ind)
+}
+
+func (s *byIndex) Less(i, j int) bool {
+	return s.ind[i] < s.ind[j]
+}
+
+func (s *byIndex) Swap(i, j int) {
+	s.ind[i], s.ind[j] = s.ind[j], s.ind[i]
+	s.vals[i], s.vals[j] = s.vals[j], s.vals[i]
+}
+
+func MatToCSR(m *Matrix) *CSR {
+	res := &CSR{rows: m.rows, cols: m.cols, rowPtr: make([]int, m.rows + 1)}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			if m.data[i * m.stride + j] != 0 {
+				res.colInd = append(res.colInd, j)
+				res.vals = append(res.vals, m.data[i * m.stride + j])
+			}
+		}
+		res.rowPtr[i + 1] = len(res.vals)
+	}
+	return res
+}
+
+func MatToCSC(m *Matrix) *CSC {
+	return MatToCSR(TransposeMat(m)).transView()
+}
+
+func (m *CSR) Dims() (int, int) {
+	return m.rows, m.cols
+}
+
+func (m *CSR) NNZ() int {
+	return len(m.vals)
+}
+
+func (m *CSR) At(i, j int) float64 {
+	ind := m.colInd[m.rowPtr[i] : m.rowPtr[i + 1]]
+	k := sort.SearchInts(ind, j)
+	if k < len(ind) && ind[k] == j {
+		return m.vals[m.rowPtr[i] + k]
+	}
+	return 0
+}
+
+// column indices and values of the nonzeros of row i, not copies
+func (m *CSR) Row(i int) ([]int, []float64) {
+	return m.colInd[m.rowPtr[i] : m.rowPtr[i + 1]], m.vals[m.rowPtr[i] : m.rowPtr[i + 1]]
+}
+
+func (m *CSR) MulVec(dst, x []float64) {
+	for i := 0; i < m.rows; i++ {
+		var sum float64
+		for k := m.rowPtr[i]; k < m.rowPtr[i + 1]; k++ {
+			sum += m.vals[k] * x[m.colInd[k]]
+		}
+		dst[i] = sum
+	}
+}
+
+func (m *CSR) MulVecTrans(dst, x []float64) {
+	for j := 0; j < m.cols; j++ {
+		dst[j] = 0
+	}
+	for i := 0; i < m.rows; i++ {
+		for k := m.rowPtr[i]; k < m.rowPtr[i + 1]; k++ {
+			dst[m.colInd[k]] += m.vals[k] * x[i]
+		}
+	}
+}
+
+// the same arrays read column-wise are the transpose in CSC
+func (m *CSR) transView() *CSC {
+	return &CSC{m.cols, m.rows, m.rowPtr, m.colInd, m.vals}
+}
+
+func (m *CSR) T() *CSR {
+	return m.ToCSC().transView()
+}
+
+func (m *CSR) ToCSC() *CSC {
+	rowInd := make([]int, len(m.vals))
+	for i := 0; i < m.rows; i++ {
+		for k := m.rowPtr[i]; k < m.rowPtr[i + 1]; k++ {
+			rowInd[k] = i
+		}
+	}
+	ptr, ind, vals := compress(m.cols, m.colInd, rowInd, m.vals)
+	return &CSC{m.rows, m.cols, ptr, ind, vals}
+}
+
+func (m *CSR) ToMat() *Matrix {
+	data := init2dSlice(m.rows, m.cols)
+	for i := 0; i < m.rows; i++ {
+		for k := m.rowPtr[i]; k < m.rowPtr[i + 1]; k++ {
+			data[i][m.colInd[k]] = m.vals[k]
+		}
+	}
+	return matOfRows(data, m.rows, m.cols)
+}
+
+func (m *CSR) Add(b *CSR) (*CSR, error) {
+	if m.rows != b.rows || m.cols != b.cols {
+		return nil, errors.New("matrixs dims don't match")
+	}
+	res := &CSR{rows: m.rows, cols: m.cols, rowPtr: make([]int, m.rows + 1)}
+	for i := 0; i < m.rows; i++ {
+		k, l := m.rowPtr[i], b.rowPtr[i]
+		for k < m.rowPtr[i + 1] || l < b.rowPtr[i + 1] {
+			var j int
+			var v float64
+			switch {
+			case l == b.rowPtr[i + 1] || k < m.rowPtr[i + 1] && m.colInd[k] < b.colInd[l]:
+				j, v = m.colInd[k], m.vals[k]
+				k++
+			case k == m.rowPtr[i + 1] || b.colInd[l] < m.colInd[k]:
+				j, v = b.colInd[l], b.vals[l]
+				l++
+			default:
+				j, v = m.colInd[k], m.vals[k] + b.vals[l]
+				k++
+				l++
+			}
+			if v != 0 {
+				res.colInd = append(res.colInd, j)
+				res.vals = append(res.vals, v)
+			}
+		}
+		res.rowPtr[i + 1] = len(res.vals)
+	}
+	return res, nil
+}
+
+func (m *CSR) Scale(c float64) *CSR {
+	if c == 0 {
+		return &CSR{rows: m.rows, cols: m.cols, rowPtr: make([]int, m.rows + 1)}
+	}
+	res := &CSR{m.rows, m.cols, make([]int, len(m.rowPtr)), make([]int, len(m.colInd)), make([]float64, len(m.vals))}
+	copy(res.rowPtr, m.rowPtr)
+	copy(res.colInd, m.colInd)
+	for k, v := range m.vals {
+		res.vals[k] = c * v
+	}
+	return res
+}
+
+func (m *CSR) IsDiagDominant() bool {
+	if m.rows != m.cols {
+		return false
+	}
+	for i := 0; i < m.rows; i++ {
+		var diag, sum float64
+		for k := m.rowPtr[i]; k < m.rowPtr[i + 1]; k++ {
+			if m.colInd[k] == i {
+				diag = math.Abs(m.vals[k])
+			} else {
+				sum += math.Abs(m.vals[k])
+			}
+		}
+		if diag < sum {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *CSR) IsSymmetric() bool {
+	if m.rows != m.cols {
+		return false
+	}
+	t := m.ToCSC()
+	for k := range m.rowPtr {
+		if m.rowPtr[k] != t.colPtr[k] {
+			return false
+		}
+	}
+	for k := range m.vals {
+		if m.colInd[k] != t.rowInd[k] || m.vals[k] != t.vals[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// CSC operations are the CSR ones on the transposed view
+func (m *CSC) transView() *CSR {
+	return &CSR{m.cols, m.rows, m.colPtr, m.rowInd, m.vals}
+}
+
+func (m *CSC) Dims() (int, int) {
+	return m.rows, m.cols
+}
+
+func (m *CSC) NNZ() int {
+	return len(m.vals)
+}
+
+func (m *CSC) At(i, j int) float64 {
+	return m.transView().At(j, i)
+}
+
+// row indices and values of the nonzeros of column j, not copies
+func (m *CSC) Col(j int) ([]int, []float64) {
+	return m.transView().Row(j)
+}
+
+func (m *CSC) MulVec(dst, x []float64) {
+	m.transView().MulVecTrans(dst, x)
+}
+
+func (m *CSC) MulVecTrans(dst, x []float64) {
+	m.transView().MulVec(dst, x)
+}
+
+func (m *CSC) T() *CSC {
+	return m.ToCSR().transView()
+}
+
+func (m *CSC) ToCSR() *CSR {
+	return m.transView().ToCSC().transView()
+}
+
+func (m *CSC) ToMat() *Matrix {
+	return TransposeMat(m.transView().ToMat())
+}
+
+func (m *CSC) Add(b *CSC) (*CSC, error) {
+	res, err := m.transView().Add(b.transView())
+	if err != nil {
+		return nil, err
+	}
+	return res.transView(), nil
+}
+
+func (m *CSC) Scale(c float64) *CSC {
+	return m.transView().Scale(c).transView()
+}
+
+func (m *CSC) IsDiagDominant() bool {
+	if m.rows != m.cols {
+		return false
+	}
+	diag, sum := make([]float64, m.rows), make([]float64, m.rows)
+	for j := 0; j < m.cols; j++ {
+		for k := m.colPtr[j]; k < m.colPtr[j + 1]; k++ {
+			if i := m.rowInd[k]; i == j {
+				diag[i] = math.Abs(m.vals[k])
+			} else {
+				sum[i] += math.Abs(m.vals[k])
+			}
+		}
+	}
+	for i := range diag {
+		if diag[i] < sum[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *CSC) IsSymmetric() bool {
+	return m.transView().IsSymmetric()
+}
+
+// Gaussian elimination with partial pivoting on sparse rows, only the
+// nonzeros and the fill-in they produce are stored
+func sparseGauss(a *CSR, f []float64) ([]float64, error) {
+	n := a.rows
+	rows := make([]map[int]float64, n)
+	// rows not yet pivoted that have a nonzero in the column
+	colRows := make([]map[int]bool, n)
+	for j := range colRows {
+		colRows[j] = map[int]bool{}
+	}
+	for i := 0; i < n; i++ {
+		rows[i] = make(map[int]float64, a.rowPtr[i + 1] - a.rowPtr[i])
+		for k := a.rowPtr[i]; k < a.rowPtr[i + 1]; k++ {
+			rows[i][a.colInd[k]] = a.vals[k]
+			colRows[a.colInd[k]][i] = true
+		}
+	}
+	b := make([]float64, n)
+	copy(b, f)
+
+	order := make([]int, n)
+	for k := 0; k < n; k++ {
+		p, pivMod := -1, 0.
+		for r := range colRows[k] {
+			if v := math.Abs(rows[r][k]); v > pivMod || v == pivMod && r < p {
+				p, pivMod = r, v
+			}
+		}
+		if p < 0 || pivMod == 0 {
+			return nil, errors.New("system is not inconsistent")
+		}
+		order[k] = p
+		for j := range rows[p] {
+			delete(colRows[j], p)
+		}
+
+		piv := rows[p][k]
+		for r := range colRows[k] {
+			c := rows[r][k] / piv
+			for j, v := range rows[p] {
+				if j == k {
+					continue
+				}
+				nv := rows[r][j] - c * v
+				if nv == 0 {
+					delete(rows[r], j)
+					delete(colRows[j], r)
+				} else {
+					rows[r][j] = nv
+					colRows[j][r] = true
+				}
+			}
+			delete(rows[r], k)
+			delete(colRows[k], r)
+			b[r] -= c * b[p]
+		}
+	}
+
+	// columns in increasing order, map order would change the sums
+	// from run to run
+	x := make([]float64, n)
+	var cols []int
+	for k := n - 1; k >= 0; k-- {
+		p := order[k]
+		cols = cols[ : 0]
+		for j := range rows[p] {
+			if j != k {
+				cols = append(cols, j)
+			}
+		}
+		sort.Ints(cols)
+		sum := b[p]
+		for _, j := range cols {
+			sum -= rows[p][j] * x[j]
+		}
+		x[k] = sum / rows[p][k]
+	}
+
+	return x, nil
+}
@@ -0,0 +1,242 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// zero fields take defaults: AMDOrdering and PivotThreshold = 1, which is
+// plain partial pivoting; with a smaller threshold the diagonal entry is
+// kept as pivot while it is at least PivotThreshold times the column maximum
+type SparseLUOptions struct {
+	Ordering       int
+	PivotThreshold float64
+}
+
+// NNZL doesn't count the unit diagonal of L, Fill is NNZL + NNZU - NNZA
+type SparseLUStats struct {
+	NNZA, NNZL, NNZU int
+	Fill             int
+}
+
+// P A Q = L U with L unit lower and U upper triangular, both stored by
+// columns; SparseLUFactor keeps a scratch vector for the solves, so it
+// mustn't be used by several goroutines at once
+type SparseLUFactor struct {
+	l, u  *CSC
+	pinv  []int
+	q     Permutation
+	n     int
+	stats SparseLUStats
+	work  []float64
+}
+
+// left-looking (Gilbert-Peierls) LU: column k of L and U comes from a sparse
+// triangular solve with the columns of L computed so far
+func FactorizeSparseLU(a *CSR, opts *SparseLUOptions) (*SparseLUFactor, error) {
+	if a.rows != a.cols {
+		return nil, errors.New("matrix isn't square")
+	}
+	n := a.rows
+
+	ordering, tol := AMDOrdering, 1.
+	if opts != nil {
+		ordering = opts.Ordering
+		if opts.PivotThreshold > 0 {
+			tol = math.Min(opts.PivotThreshold, 1)
+		}
+	}
+	q, err := Ordering(a, ordering)
+	if err != nil {
+		return nil, err
+	}
+
+	c := a.ToCSC()
+	l := &CSC{rows: n, cols: n, colPtr: make([]int, n + 1)}
+	u := &CSC{rows: n, cols: n, colPtr: make([]int, n + 1)}
+	pinv := make([]int, n)
+	for i := range pinv {
+		pinv[i] = -1
+	}
+
+	x := make([]float64, n)
+	mark := make([]bool, n)
+	reach := make([]int, 0, n)
+
+	for k := 0; k < n; k++ {
+		col := q[k]
+
+		// nonzero pattern of x = L \ A[:, col] in topological order
+		reach = reach[ : 0]
+		for p := c.colPtr[col]; p < c.colPtr[col + 1]; p++ {
+			if i := c.rowInd[p]; !mark[i] {
+				reach = luReach(l, pinv, i, mark, reach)
+			}
+		}
+		for p := c.colPtr[col]; p < c.colPtr[col + 1]; p++ {
+			x[c.rowInd[p]] = c.vals[p]
+		}
+
+		// reach holds the nodes in reverse topological order
+		for t := len(reach) - 1; t >= 0; t-- {
+			i := reach[t]
+			mark[i] = false
+			j := pinv[i]
+			if j < 0 {
+				continue
+			}
+			// L stores the unit diagonal first
+			for p := l.colPtr[j] + 1; p < l.colPtr[j + 1]; p++ {
+				x[l.rowInd[p]] -= l.vals[p] * x[i]
+			}
+		}
+
+		ipiv, maxAbs := -1, -1.
+		for _, i := range reach {
+			if pinv[i] < 0 {
+				if v := math.Abs(x[i]); v > maxAbs {
+					ipiv, maxAbs = i, v
+				}
+			} else if x[i] != 0 {
+				u.rowInd = append(u.rowInd, pinv[i])
+				u.vals = append(u.vals, x[i])
+			}
+		}
+		if ipiv < 0 || maxAbs == 0 {
+			for _, i := range reach {
+				x[i] = 0
+			}
+			return nil, &SingularMatrixError{Rank: k}
+		}
+		if pinv[col] < 0 && math.Abs(x[col]) >= tol * maxAbs {
+			ipiv = col
+		}
+
+		piv := x[ipiv]
+		u.rowInd = append(u.rowInd, k)
+		u.vals = append(u.vals, piv)
+		u.colPtr[k + 1] = len(u.vals)
+
+		pinv[ipiv] = k
+		l.rowInd = append(l.rowInd, ipiv)
+		l.vals = append(l.vals, 1)
+		for _, i := range reach {
+			if pinv[i] < 0 && x[i] != 0 {
+				l.rowInd = append(l.rowInd, i)
+				l.vals = append(l.vals, x[i] / piv)
+			}
+			x[i] = 0
+		}
+		l.colPtr[k + 1] = len(l.vals)
+	}
+
+	// L row indices into pivot order, after sorting the unit diagonal
+	// stays first in every column of L and the pivot last in U
+	for p, i := range l.rowInd {
+		l.rowInd[p] = pinv[i]
+	}
+	for j := 0; j < n; j++ {
+		sort.Sort(&byIndex{l.rowInd[l.colPtr[j] : l.colPtr[j + 1]], l.vals[l.colPtr[j] : l.colPtr[j + 1]]})
+		sort.Sort(&byIndex{u.rowInd[u.colPtr[j] : u.colPtr[j + 1]], u.vals[u.colPtr[j] : u.colPtr[j + 1]]})
+	}
+
+	stats := SparseLUStats{NNZA: a.NNZ(), NNZL: l.NNZ() - n, NNZU: u.NNZ()}
+	stats.Fill = stats.NNZL + stats.NNZU - stats.NNZA
+
+	return &SparseLUFactor{l, u, pinv, q, n, stats, make([]float64, n)}, nil
+}
+
+// depth-first search from row i through the columns of L already computed,
+// appends the visited rows in post order
+func luReach(l *CSC, pinv []int, i int, mark []bool, reach []int) []int {
+	mark[i] = true
+	if j := pinv[i]; j >= 0 {
+		for p := l.colPtr[j] + 1; p < l.colPtr[j + 1]; p++ {
+			if r := l.rowInd[p]; !mark[r] {
+				reach = luReach(l, pinv, r, mark, reach)
+			}
+		}
+	}
+	return append(reach, i)
+}
+
+func (f *SparseLUFactor) Dim() int {
+	return f.n
+}
+
+func (f *SparseLUFactor) Stats() SparseLUStats {
+	return f.stats
+}
+
+// row i of P A is row RowPerm()[i] of A
+func (f *SparseLUFactor) RowPerm() Permutation {
+	p := make(Permutation, f.n)
+	for i, k := range f.pinv {
+		p[k] = i
+	}
+	return p
+}
+
+// column i of A Q is column ColPerm()[i] of A
+func (f *SparseLUFactor) ColPerm() Permutation {
+	return append(Permutation(nil), f.q...)
+}
+
+func (f *SparseLUFactor) L() *CSC {
+	return f.l
+}
+
+func (f *SparseLUFactor) U() *CSC {
+	return f.u
+}
+
+// dst may alias b
+func (f *SparseLUFactor) SolveTo(dst, b []float64) error {
+	if len(b) != f.n || len(dst) != f.n {
+		return errors.New("factor and vector dims don't match")
+	}
+	x := f.work
+
+	for i, k := range f.pinv {
+		x[k] = b[i]
+	}
+	for j := 0; j < f.n; j++ {
+		for p := f.l.colPtr[j] + 1; p < f.l.colPtr[j + 1]; p++ {
+			x[f.l.rowInd[p]] -= f.l.vals[p] * x[j]
+		}
+	}
+	// U stores the diagonal last
+	for j := f.n - 1; j >= 0; j-- {
+		last := f.u.colPtr[j + 1] - 1
+		x[j] /= f.u.vals[last]
+		for p := f.u.colPtr[j]; p < last; p++ {
+			x[f.u.rowInd[p]] -= f.u.vals[p] * x[j]
+		}
+	}
+	for k, j := range f.q {
+		dst[j] = x[k]
+	}
+
+	return nil
+}
+
+func (f *SparseLUFactor) Solve(b []float64) ([]float64, error) {
+	x := make([]float64, f.n)
+	if err := f.SolveTo(x, b); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (f *SparseLUFactor) SolveMany(b *Matrix) (*Matrix, error) {
+	return solveMany(f.n, f.n, b, f.SolveTo)
+}
+
+func (f *SparseLUFactor) Det() (float64, error) {
+	res := f.RowPerm().Sign() * f.q.Sign()
+	for j := 0; j < f.n; j++ {
+		res *= f.u.vals[f.u.colPtr[j + 1] - 1]
+	}
+	return res, nil
+}
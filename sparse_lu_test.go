@@ -0,0 +1,123 @@
+package algnum
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSparseLU(t *testing.T) {
+	n := 80
+	data := randSparseData(n, n, 0.05)
+	for i := 0; i < n; i++ {
+		data[i][(i * 7 + 3) % n] += 10 + float64(i % 5)
+		data[i][i] += float64(rand.Intn(3))
+	}
+	a := MatToCSR(matOfRows(data, n, n))
+	f := randFree(n, 1, 10)
+
+	lapack, err := lapackSolve(data, n, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	det := lapackDet(data, n)
+
+	for _, kind := range []int{NaturalOrdering, AMDOrdering, RCMOrdering, NestedDissectionOrdering} {
+		for _, threshold := range []float64{1, 0.1} {
+			lu, err := FactorizeSparseLU(a, &SparseLUOptions{Ordering: kind, PivotThreshold: threshold})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			x, err := lu.Solve(f)
+			if err != nil {
+				t.Fatal(err)
+			} else if !VectsEq(lapack, x, 1e-8) {
+				t.Fatalf("result is wrong with ordering %d: expected\n %s,\ngot\n %s", kind, VectToStr(lapack), VectToStr(x))
+			}
+
+			// P A Q = L U
+			pa := matOfRows(init2dSlice(n, n), n, n)
+			p, q := lu.RowPerm(), lu.ColPerm()
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					pa.data[i * pa.stride + j] = data[p[i]][q[j]]
+				}
+			}
+			prod, _ := MatMul(lu.L().ToMat(), lu.U().ToMat())
+			if !MatsEq(pa, prod, 1e-9) {
+				t.Fatalf("factors are wrong with ordering %d", kind)
+			}
+
+			d, _ := lu.Det()
+			if math.Abs(d - det) > 1e-8 * math.Abs(det) {
+				t.Fatalf("result is wrong: expected det %e, got %e", det, d)
+			}
+		}
+	}
+
+	lu, _ := FactorizeSparseLU(a, nil)
+	b := matOfRows(randData(n, 3, 1, 10), n, 3)
+	xs, err := lu.SolveMany(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xj, bj := make([]float64, n), make([]float64, n)
+	for j := 0; j < 3; j++ {
+		for i := 0; i < n; i++ {
+			xj[i] = xs.data[i * xs.stride + j]
+		}
+		a.MulVec(bj, xj)
+		for i := 0; i < n; i++ {
+			if math.Abs(bj[i] - b.data[i * b.stride + j]) > 1e-8 {
+				t.Fatal("result is wrong: A * X != B")
+			}
+		}
+	}
+
+	// dst may alias b
+	y := append([]float64(nil), f...)
+	if err := lu.SolveTo(y, y); err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapack, y, 1e-8) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(y))
+	}
+
+	singular := MatToCSR(matOfRows([][]float64{{1, 2, 0}, {2, 4, 0}, {0, 0, 1}}, 3, 3))
+	if _, err := FactorizeSparseLU(singular, nil); err == nil {
+		t.Fatal("expected singular matrix error")
+	} else {
+		t.Log("sparse lu works correct")
+	}
+}
+
+func TestSparseLUFill(t *testing.T) {
+	k := 15
+	lap := laplacian2dCSR(k)
+	f := randFree(k * k, 1, 10)
+	lapack, _ := lapackSolve(lap.ToMat().rowSlices(), k * k, f)
+
+	fill := map[int]int{}
+	for _, kind := range []int{NaturalOrdering, AMDOrdering, RCMOrdering, NestedDissectionOrdering} {
+		lu, err := FactorizeSparseLU(lap, &SparseLUOptions{Ordering: kind, PivotThreshold: 0.01})
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, _ := lu.Solve(f)
+		if !VectsEq(lapack, x, 1e-8) {
+			t.Fatalf("result is wrong with ordering %d", kind)
+		}
+		s := lu.Stats()
+		if s.NNZA != lap.NNZ() || s.Fill != s.NNZL + s.NNZU - s.NNZA {
+			t.Fatalf("stats are wrong: %+v", s)
+		}
+		fill[kind] = s.Fill
+		t.Logf("ordering %d: %+v", kind, s)
+	}
+
+	if fill[AMDOrdering] >= fill[NaturalOrdering] || fill[NestedDissectionOrdering] >= fill[NaturalOrdering] {
+		t.Fatalf("fill-reducing orderings didn't reduce fill: %v", fill)
+	} else {
+		t.Log("fill-reducing orderings work correct")
+	}
+}
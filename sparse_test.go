@@ -0,0 +1,245 @@
+package algnum
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randSparseData(rows, cols int, density float64) [][]float64 {
+	data := init2dSlice(rows, cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			if rand.Float64() < density {
+				data[i][j] = float64(rand.Intn(19) - 9)
+			}
+		}
+	}
+	return data
+}
+
+func laplacian2dCSR(k int) *CSR {
+	coo, _ := InitCOO(k * k, k * k)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			r := i * k + j
+			_ = coo.Append(r, r, 4)
+			if i > 0 {
+				_ = coo.Append(r, r - k, -1)
+			}
+			if i < k - 1 {
+				_ = coo.Append(r, r + k, -1)
+			}
+			if j > 0 {
+				_ = coo.Append(r, r - 1, -1)
+			}
+			if j < k - 1 {
+				_ = coo.Append(r, r + 1, -1)
+			}
+		}
+	}
+	return coo.ToCSR()
+}
+
+func TestCOO(t *testing.T) {
+	coo, _ := InitCOO(3, 4)
+	_ = coo.Append(2, 1, 5)
+	_ = coo.Append(0, 3, 1)
+	_ = coo.Append(2, 1, -2)
+	_ = coo.Append(1, 0, 7)
+	_ = coo.Append(1, 2, 3)
+	_ = coo.Append(1, 2, -3)
+	if err := coo.Append(3, 0, 1); err == nil {
+		t.Fatal("expected index out of range error")
+	}
+
+	expectedData := [][]float64{
+		{0, 0, 0, 1},
+		{7, 0, 0, 0},
+		{0, 3, 0, 0},
+	}
+	expected, _ := InitMat(expectedData)
+
+	csr := coo.ToCSR()
+	if !MatsEq(expected, csr.ToMat(), 1e-12) || csr.NNZ() != 3 {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", expected.ToStr(), csr.ToMat().ToStr())
+	}
+	csc := coo.ToCSC()
+	if !MatsEq(expected, csc.ToMat(), 1e-12) || csc.NNZ() != 3 {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", expected.ToStr(), csc.ToMat().ToStr())
+	} else {
+		t.Log("coo builder works correct")
+	}
+}
+
+func TestCSR(t *testing.T) {
+	data := randSparseData(30, 20, 0.2)
+	m := matOfRows(data, 30, 20)
+	csr := MatToCSR(m)
+	csc := MatToCSC(m)
+
+	if !MatsEq(m, csr.ToMat(), 1e-12) || !MatsEq(m, csc.ToMat(), 1e-12) || !MatsEq(m, csc.ToCSR().ToMat(), 1e-12) || !MatsEq(m, csr.ToCSC().ToMat(), 1e-12) {
+		t.Fatal("conversion is wrong")
+	}
+	for i := 0; i < 30; i++ {
+		for j := 0; j < 20; j++ {
+			if csr.At(i, j) != data[i][j] || csc.At(i, j) != data[i][j] {
+				t.Fatalf("element (%d, %d) is wrong", i, j)
+			}
+		}
+	}
+
+	x, y := randFree(20, -10, 10), randFree(30, -10, 10)
+	expected, res := make([]float64, 30), make([]float64, 30)
+	for i := 0; i < 30; i++ {
+		expected[i] = dot(data[i], x)
+	}
+	for _, op := range []LinearOperator{csr, csc} {
+		op.MulVec(res, x)
+		if !VectsEq(expected, res, 1e-12) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expected), VectToStr(res))
+		}
+	}
+
+	tData := transpose2dSlice(data, 30, 20)
+	expectedT, resT := make([]float64, 20), make([]float64, 20)
+	for j := 0; j < 20; j++ {
+		expectedT[j] = dot(tData[j], y)
+	}
+	for _, op := range []LinearOperator{csr, csc} {
+		op.MulVecTrans(resT, y)
+		if !VectsEq(expectedT, resT, 1e-12) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(expectedT), VectToStr(resT))
+		}
+	}
+	tMat := matOfRows(tData, 20, 30)
+	if !MatsEq(tMat, csr.T().ToMat(), 1e-12) || !MatsEq(tMat, csc.T().ToMat(), 1e-12) {
+		t.Fatal("transpose is wrong")
+	}
+
+	bData := randSparseData(30, 20, 0.2)
+	b := matOfRows(bData, 30, 20)
+	sumData := init2dSlice(30, 20)
+	for i := 0; i < 30; i++ {
+		for j := 0; j < 20; j++ {
+			sumData[i][j] = data[i][j] + 2 * bData[i][j]
+		}
+	}
+	sum := matOfRows(sumData, 30, 20)
+	csrSum, err := csr.Add(MatToCSR(b).Scale(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cscSum, err := csc.Add(MatToCSC(b).Scale(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !MatsEq(sum, csrSum.ToMat(), 1e-12) || !MatsEq(sum, cscSum.ToMat(), 1e-12) {
+		t.Fatal("sum is wrong")
+	}
+	if _, err := csr.Add(csr.T()); err == nil {
+		t.Fatal("expected dims error")
+	} else {
+		t.Log("csr and csc work correct")
+	}
+}
+
+func TestCSRChecks(t *testing.T) {
+	lap := laplacian2dCSR(5)
+	if !lap.IsSymmetric() || !lap.IsDiagDominant() || !lap.ToCSC().IsSymmetric() || !lap.ToCSC().IsDiagDominant() {
+		t.Fatal("laplacian is symmetric and diagonally dominant")
+	}
+
+	for k := 0; k < 20; k++ {
+		data := randSparseData(8, 8, 0.3)
+		for i := 0; i < 8; i++ {
+			data[i][i] = float64(rand.Intn(30))
+		}
+		m, _ := InitMat(data)
+		csr, csc := MatToCSR(m), MatToCSC(m)
+		if csr.IsSymmetric() != m.IsSymmetric() || csc.IsSymmetric() != m.IsSymmetric() {
+			t.Fatalf("symmetry check is wrong for\n %s", m.ToStr())
+		}
+		if csr.IsDiagDominant() != m.IsDiagDominant() || csc.IsDiagDominant() != m.IsDiagDominant() {
+			t.Fatalf("diagonal dominance check is wrong for\n %s", m.ToStr())
+		}
+	}
+
+	t.Log("sparse checks work correct")
+}
+
+func TestSparseSolvers(t *testing.T) {
+	k := 10
+	lap := laplacian2dCSR(k)
+	dense := lap.ToMat()
+	f := randFree(k * k, 1, 10)
+
+	lapack, err := lapackSolve(dense.rowSlices(), k * k, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, a := range []ElementAccessor{lap, lap.ToCSC()} {
+		jacobi, err := Jacobi(a, f, nil)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(lapack, jacobi.X, 1e-6) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(jacobi.X))
+		}
+		seidel, err := Seidel(a, f, nil)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(lapack, seidel.X, 1e-6) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(seidel.X))
+		}
+		sor, err := SOR(a, f, 1.5, nil)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(lapack, sor.X, 1e-6) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(sor.X))
+		}
+		gauss, err := Gauss(a, f)
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(lapack, gauss, 1e-9) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(gauss))
+		}
+	}
+	// row sweeps over a CSC go through its CSR form instead of At
+	if _, ok := byRows(lap.ToCSC()).(*CSR); !ok {
+		t.Fatal("csc isn't converted for the row-wise solvers")
+	}
+
+	// nonsymmetric with zero diagonal entries needs pivoting
+	n := 60
+	data := randSparseData(n, n, 0.1)
+	for i := 0; i < n; i++ {
+		data[i][(i + 1) % n] = 10 + float64(i)
+	}
+	fN := randFree(n, 1, 10)
+	lapackN, err := lapackSolve(data, n, fN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Gauss(MatToCSR(matOfRows(data, n, n)), fN)
+	if err != nil {
+		t.Fatal(err)
+	} else if !VectsEq(lapackN, res, 1e-8) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapackN), VectToStr(res))
+	}
+	// bit for bit the same on every run
+	for k := 0; k < 10; k++ {
+		again, _ := Gauss(MatToCSR(matOfRows(data, n, n)), fN)
+		for i := range res {
+			if again[i] != res[i] {
+				t.Fatal("sparse gauss isn't reproducible")
+			}
+		}
+	}
+
+	singular := MatToCSR(matOfRows([][]float64{{1, 2}, {2, 4}}, 2, 2))
+	if _, err := Gauss(singular, []float64{1, 1}); err == nil {
+		t.Fatal("expected singular system error")
+	} else {
+		t.Log("solvers on sparse matrices work correct")
+	}
+}
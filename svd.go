@@ -0,0 +1,524 @@
+package algnum
+
+import (
+	"errors"
+	"math"
+)
+
+const svdMaxSweeps = 75
+
+type SVDFactor struct {
+	u, v       [][]float64
+	s          []float64
+	rows, cols int
+	full       bool
+}
+
+// A = U * Sigma * V^T with singular values in decreasing order; thin mode
+// keeps k = min(rows, cols) columns of U and V, full mode completes them
+// to orthogonal matrices
+func SVD(m *Matrix, full bool) (*SVDFactor, error) {
+	if m.rows == 0 {
+		return nil, errors.New("matrix is empty")
+	}
+
+	var u, v [][]float64
+	var s []float64
+	var err error
+	if m.rows >= m.cols {
+		u, s, v, err = golubKahanSVD(m.rowSlices(), m.rows, m.cols)
+	} else {
+		// A^T = V * Sigma * U^T
+		v, s, u, err = golubKahanSVD(transpose2dSlice(m.rowSlices(), m.rows, m.cols), m.cols, m.rows)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if full {
+		u = completeBasis(u, m.rows)
+		v = completeBasis(v, m.cols)
+	}
+
+	return &SVDFactor{u: u, v: v, s: s, rows: m.rows, cols: m.cols, full: full}, nil
+}
+
+func (f *SVDFactor) Values() []float64 {
+	res := make([]float64, len(f.s))
+	copy(res, f.s)
+	return res
+}
+
+func (f *SVDFactor) U() *Matrix {
+	return matOfRows(copy2dSlice(f.u), len(f.u), len(f.u[0]))
+}
+
+func (f *SVDFactor) V() *Matrix {
+	return matOfRows(copy2dSlice(f.v), len(f.v), len(f.v[0]))
+}
+
+func (f *SVDFactor) VT() *Matrix {
+	rows, cols := len(f.v), len(f.v[0])
+	return matOfRows(transpose2dSlice(f.v, rows, cols), cols, rows)
+}
+
+func (f *SVDFactor) Sigma() *Matrix {
+	rows, cols := len(f.s), len(f.s)
+	if f.full {
+		rows, cols = f.rows, f.cols
+	}
+	res, _ := InitMatOfDims(rows, cols)
+	for i, si := range f.s {
+		res.data[i * res.stride + i] = si
+	}
+	return res
+}
+
+// tol <= 0 means the default max(rows, cols) * eps * sigma_max
+func (f *SVDFactor) Rank(tol float64) int {
+	if tol <= 0 {
+		tol = float64(intMax(f.rows, f.cols)) * machEps * f.s[0]
+	}
+	rank := 0
+	for _, si := range f.s {
+		if si > tol {
+			rank++
+		}
+	}
+	return rank
+}
+
+func (f *SVDFactor) Cond2() float64 {
+	return f.s[0] / f.s[len(f.s) - 1]
+}
+
+func (f *SVDFactor) PseudoInverse(tol float64) *Matrix {
+	r := f.Rank(tol)
+	res, _ := InitMatOfDims(f.cols, f.rows)
+	for k := 0; k < r; k++ {
+		inv := 1 / f.s[k]
+		for i := 0; i < f.cols; i++ {
+			vik := f.v[i][k] * inv
+			if vik == 0 {
+				continue
+			}
+			for j := 0; j < f.rows; j++ {
+				res.data[i * res.stride + j] += vik * f.u[j][k]
+			}
+		}
+	}
+	return res
+}
+
+// x = V * Sigma^+ * U^T * b
+func (f *SVDFactor) Solve(b []float64, tol float64) ([]float64, error) {
+	if len(b) != f.rows {
+		return nil, errors.New("factor and vector dims don't match")
+	}
+
+	r := f.Rank(tol)
+	x := make([]float64, f.cols)
+	for k := 0; k < r; k++ {
+		var utb float64
+		for i := 0; i < f.rows; i++ {
+			utb += f.u[i][k] * b[i]
+		}
+		utb /= f.s[k]
+		for i := 0; i < f.cols; i++ {
+			x[i] += f.v[i][k] * utb
+		}
+	}
+	return x, nil
+}
+
+func (m *Matrix) Rank(tol float64) (int, error) {
+	f, err := SVD(m, false)
+	if err != nil {
+		return 0, err
+	}
+	return f.Rank(tol), nil
+}
+
+func (m *Matrix) PseudoInverse() (*Matrix, error) {
+	f, err := SVD(m, false)
+	if err != nil {
+		return nil, err
+	}
+	return f.PseudoInverse(0), nil
+}
+
+func (m *Matrix) Cond2() (float64, error) {
+	f, err := SVD(m, false)
+	if err != nil {
+		return -1, err
+	}
+	return f.Cond2(), nil
+}
+
+// orthonormal basis of {x: Ax = 0} as columns, an empty matrix for full column rank
+func (m *Matrix) NullSpace() (*Matrix, error) {
+	f, err := SVD(m, true)
+	if err != nil {
+		return nil, err
+	}
+
+	r := f.Rank(0)
+	return columns(f.v, r, m.cols), nil
+}
+
+// orthonormal basis of {Ax} as columns
+func (m *Matrix) Range() (*Matrix, error) {
+	f, err := SVD(m, false)
+	if err != nil {
+		return nil, err
+	}
+
+	r := f.Rank(0)
+	return columns(f.u, 0, r), nil
+}
+
+func columns(data [][]float64, from, to int) *Matrix {
+	if from == to {
+		return &Matrix{}
+	}
+	res, _ := InitMatOfDims(len(data), to - from)
+	for i := range data {
+		copy(res.row(i), data[i][from : to])
+	}
+	return res
+}
+
+// extends the orthonormal columns of q to an orthogonal dim x dim matrix
+// by taking the trailing columns of the full Q from Householder QR of q
+func completeBasis(q [][]float64, dim int) [][]float64 {
+	k := len(q[0])
+	if k == dim {
+		return q
+	}
+
+	f, _ := QR(matOfRows(q, dim, k), false)
+	full := f.Q()
+	for i := 0; i < dim; i++ {
+		copy(full.row(i)[ : k], q[i])
+	}
+	return full.rowSlices()
+}
+
+// Golub-Kahan bidiagonalization followed by implicit shifted QR on the
+// bidiagonal matrix, requires rows >= cols; returns thin U, sigma and V
+func golubKahanSVD(data [][]float64, m, n int) ([][]float64, []float64, [][]float64, error) {
+	a := copy2dSlice(data)
+	nu := intMin(m, n)
+	s := make([]float64, intMin(m + 1, n))
+	u := init2dSlice(m, nu)
+	v := init2dSlice(n, n)
+	e := make([]float64, n)
+	work := make([]float64, m)
+
+	// reduce A to bidiagonal form, storing the diagonal in s
+	// and the super-diagonal in e
+	nct := intMin(m - 1, n)
+	nrt := intMax(0, intMin(n - 2, m))
+	for k := 0; k < intMax(nct, nrt); k++ {
+		if k < nct {
+			s[k] = 0
+			for i := k; i < m; i++ {
+				s[k] = math.Hypot(s[k], a[i][k])
+			}
+			if s[k] != 0 {
+				if a[k][k] < 0 {
+					s[k] = -s[k]
+				}
+				for i := k; i < m; i++ {
+					a[i][k] /= s[k]
+				}
+				a[k][k] += 1
+			}
+			s[k] = -s[k]
+		}
+		for j := k + 1; j < n; j++ {
+			if k < nct && s[k] != 0 {
+				var t float64
+				for i := k; i < m; i++ {
+					t += a[i][k] * a[i][j]
+				}
+				t = -t / a[k][k]
+				for i := k; i < m; i++ {
+					a[i][j] += t * a[i][k]
+				}
+			}
+			e[j] = a[k][j]
+		}
+		if k < nct {
+			for i := k; i < m; i++ {
+				u[i][k] = a[i][k]
+			}
+		}
+		if k < nrt {
+			e[k] = 0
+			for i := k + 1; i < n; i++ {
+				e[k] = math.Hypot(e[k], e[i])
+			}
+			if e[k] != 0 {
+				if e[k + 1] < 0 {
+					e[k] = -e[k]
+				}
+				for i := k + 1; i < n; i++ {
+					e[i] /= e[k]
+				}
+				e[k + 1] += 1
+			}
+			e[k] = -e[k]
+			if k + 1 < m && e[k] != 0 {
+				for i := k + 1; i < m; i++ {
+					work[i] = 0
+				}
+				for j := k + 1; j < n; j++ {
+					for i := k + 1; i < m; i++ {
+						work[i] += e[j] * a[i][j]
+					}
+				}
+				for j := k + 1; j < n; j++ {
+					t := -e[j] / e[k + 1]
+					for i := k + 1; i < m; i++ {
+						a[i][j] += t * work[i]
+					}
+				}
+			}
+			for i := k + 1; i < n; i++ {
+				v[i][k] = e[i]
+			}
+		}
+	}
+
+	p := intMin(n, m + 1)
+	if nct < n {
+		s[nct] = a[nct][nct]
+	}
+	if m < p {
+		s[p - 1] = 0
+	}
+	if nrt + 1 < p {
+		e[nrt] = a[nrt][p - 1]
+	}
+	e[p - 1] = 0
+
+	// accumulate the left transformations
+	for j := nct; j < nu; j++ {
+		for i := 0; i < m; i++ {
+			u[i][j] = 0
+		}
+		u[j][j] = 1
+	}
+	for k := nct - 1; k >= 0; k-- {
+		if s[k] != 0 {
+			for j := k + 1; j < nu; j++ {
+				var t float64
+				for i := k; i < m; i++ {
+					t += u[i][k] * u[i][j]
+				}
+				t = -t / u[k][k]
+				for i := k; i < m; i++ {
+					u[i][j] += t * u[i][k]
+				}
+			}
+			for i := k; i < m; i++ {
+				u[i][k] = -u[i][k]
+			}
+			u[k][k] += 1
+			for i := 0; i < k; i++ {
+				u[i][k] = 0
+			}
+		} else {
+			for i := 0; i < m; i++ {
+				u[i][k] = 0
+			}
+			u[k][k] = 1
+		}
+	}
+
+	// accumulate the right transformations
+	for k := n - 1; k >= 0; k-- {
+		if k < nrt && e[k] != 0 {
+			for j := k + 1; j < nu; j++ {
+				var t float64
+				for i := k + 1; i < n; i++ {
+					t += v[i][k] * v[i][j]
+				}
+				t = -t / v[k + 1][k]
+				for i := k + 1; i < n; i++ {
+					v[i][j] += t * v[i][k]
+				}
+			}
+		}
+		for i := 0; i < n; i++ {
+			v[i][k] = 0
+		}
+		v[k][k] = 1
+	}
+
+	// diagonalize the bidiagonal matrix
+	pp := p - 1
+	iter := 0
+	tiny := math.Pow(2, -966)
+	for p > 0 {
+		if iter > svdMaxSweeps * n {
+			return nil, nil, nil, errors.New("svd didn't converge")
+		}
+
+		// kase 1: s[p-1] is negligible, kase 2: s[k] is negligible,
+		// kase 3: e[k-1] is negligible and a QR step is due, kase 4: e[p-2] is negligible
+		var k, kase int
+		for k = p - 2; k >= 0; k-- {
+			if math.Abs(e[k]) <= tiny + machEps * (math.Abs(s[k]) + math.Abs(s[k + 1])) {
+				e[k] = 0
+				break
+			}
+		}
+		if k == p - 2 {
+			kase = 4
+		} else {
+			var ks int
+			for ks = p - 1; ks > k; ks-- {
+				var t float64
+				if ks != p {
+					t += math.Abs(e[ks])
+				}
+				if ks != k + 1 {
+					t += math.Abs(e[ks - 1])
+				}
+				if math.Abs(s[ks]) <= tiny + machEps * t {
+					s[ks] = 0
+					break
+				}
+			}
+			if ks == k {
+				kase = 3
+			} else if ks == p - 1 {
+				kase = 1
+			} else {
+				kase = 2
+				k = ks
+			}
+		}
+		k++
+
+		switch kase {
+		case 1:
+			f := e[p - 2]
+			e[p - 2] = 0
+			for j := p - 2; j >= k; j-- {
+				t := math.Hypot(s[j], f)
+				cs, sn := s[j] / t, f / t
+				s[j] = t
+				if j != k {
+					f = -sn * e[j - 1]
+					e[j - 1] = cs * e[j - 1]
+				}
+				rotateCols(v, j, p - 1, cs, sn)
+			}
+		case 2:
+			f := e[k - 1]
+			e[k - 1] = 0
+			for j := k; j < p; j++ {
+				t := math.Hypot(s[j], f)
+				cs, sn := s[j] / t, f / t
+				s[j] = t
+				f = -sn * e[j]
+				e[j] = cs * e[j]
+				rotateCols(u, j, k - 1, cs, sn)
+			}
+		case 3:
+			scale := math.Max(math.Max(math.Max(math.Max(
+				math.Abs(s[p - 1]), math.Abs(s[p - 2])), math.Abs(e[p - 2])),
+				math.Abs(s[k])), math.Abs(e[k]))
+			sp := s[p - 1] / scale
+			spm1 := s[p - 2] / scale
+			epm1 := e[p - 2] / scale
+			sk := s[k] / scale
+			ek := e[k] / scale
+			b := ((spm1 + sp) * (spm1 - sp) + epm1 * epm1) / 2
+			c := (sp * epm1) * (sp * epm1)
+			var shift float64
+			if b != 0 || c != 0 {
+				shift = math.Sqrt(b * b + c)
+				if b < 0 {
+					shift = -shift
+				}
+				shift = c / (b + shift)
+			}
+			f := (sk + sp) * (sk - sp) + shift
+			g := sk * ek
+
+			for j := k; j < p - 1; j++ {
+				t := math.Hypot(f, g)
+				cs, sn := f / t, g / t
+				if j != k {
+					e[j - 1] = t
+				}
+				f = cs * s[j] + sn * e[j]
+				e[j] = cs * e[j] - sn * s[j]
+				g = sn * s[j + 1]
+				s[j + 1] = cs * s[j + 1]
+				rotateCols(v, j, j + 1, cs, sn)
+
+				t = math.Hypot(f, g)
+				cs, sn = f / t, g / t
+				s[j] = t
+				f = cs * e[j] + sn * s[j + 1]
+				s[j + 1] = -sn * e[j] + cs * s[j + 1]
+				g = sn * e[j + 1]
+				e[j + 1] = cs * e[j + 1]
+				if j < m - 1 {
+					rotateCols(u, j, j + 1, cs, sn)
+				}
+			}
+			e[p - 2] = f
+			iter++
+		case 4:
+			// make the singular value positive and order them
+			if s[k] <= 0 {
+				if s[k] < 0 {
+					s[k] = -s[k]
+				} else {
+					s[k] = 0
+				}
+				for i := 0; i <= pp; i++ {
+					v[i][k] = -v[i][k]
+				}
+			}
+			for k < pp {
+				if s[k] >= s[k + 1] {
+					break
+				}
+				s[k], s[k + 1] = s[k + 1], s[k]
+				if k < n - 1 {
+					swapCols(v, k, k + 1)
+				}
+				if k < m - 1 {
+					swapCols(u, k, k + 1)
+				}
+				k++
+			}
+			iter = 0
+			p--
+		}
+	}
+
+	return u, s[ : nu], v, nil
+}
+
+// (col j, col k) = (cs * col j + sn * col k, -sn * col j + cs * col k)
+func rotateCols(a [][]float64, j, k int, cs, sn float64) {
+	for i := range a {
+		t := cs * a[i][j] + sn * a[i][k]
+		a[i][k] = -sn * a[i][j] + cs * a[i][k]
+		a[i][j] = t
+	}
+}
+
+func swapCols(a [][]float64, j, k int) {
+	for i := range a {
+		a[i][j], a[i][k] = a[i][k], a[i][j]
+	}
+}
@@ -0,0 +1,148 @@
+package algnum
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"testing"
+)
+
+func lapackSingularValues(m [][]float64, rows, cols int) []float64 {
+	var mVec []float64
+	for i := 0; i < rows; i++ {
+		mVec = append(mVec, m[i]...)
+	}
+	gonumA := mat.NewDense(rows, cols, mVec)
+
+	var svd mat.SVD
+	svd.Factorize(gonumA, mat.SVDNone)
+
+	return svd.Values(nil)
+}
+
+func checkSVD(t *testing.T, a *Matrix, f *SVDFactor, eps float64) {
+	u, sigma, vt := f.U(), f.Sigma(), f.VT()
+
+	utu, _ := MatMul(TransposeMat(u), u)
+	e, _ := IdentityMat(u.cols)
+	if !MatsEq(utu, e, eps) {
+		t.Fatalf("U isn't orthogonal:\n %s", utu.ToStr())
+	}
+	vvt, _ := MatMul(vt, f.V())
+	e, _ = IdentityMat(vt.rows)
+	if !MatsEq(vvt, e, eps) {
+		t.Fatalf("V isn't orthogonal:\n %s", vvt.ToStr())
+	}
+
+	us, _ := MatMul(u, sigma)
+	usvt, _ := MatMul(us, vt)
+	if !MatsEq(usvt, a, eps) {
+		sub, _ := MatsSub(usvt, a)
+		t.Fatalf("result is wrong: U * Sigma * V^T - A norm %0.15f", sub.Norm(EuclideanNorm))
+	}
+}
+
+func TestSVD(t *testing.T) {
+	for _, dims := range [][2]int{{30, 20}, {20, 30}, {25, 25}, {1, 7}, {7, 1}} {
+		rows, cols := dims[0], dims[1]
+		data := randData(rows, cols, -10, 10)
+		a, _ := InitMat(data)
+
+		for _, full := range []bool{false, true} {
+			f, err := SVD(a, full)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkSVD(t, a, f, 1e-9)
+
+			lapack := lapackSingularValues(data, rows, cols)
+			if !VectsEq(f.Values(), lapack, 1e-9) {
+				t.Fatalf("singular values are wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(f.Values()))
+			}
+		}
+	}
+	t.Log("svd works correct")
+}
+
+func TestMatrix_Rank(t *testing.T) {
+	data := [][]float64{
+		{1, 2, 3, 1},
+		{4, 5, 9, 0},
+		{7, 8, 15, 2},
+		{1, 0, 1, 5},
+		{2, 2, 4, 1},
+	}
+	a, _ := InitMat(data)
+
+	rank, err := a.Rank(0)
+	if err != nil {
+		t.Fatal(err)
+	} else if rank != 3 {
+		t.Fatalf("rank is wrong: expected %d, got %d", 3, rank)
+	}
+
+	null, err := a.NullSpace()
+	if err != nil {
+		t.Fatal(err)
+	} else if null.rows != 4 || null.cols != 1 {
+		t.Fatalf("null space dims are wrong: expected 4x1, got %dx%d", null.rows, null.cols)
+	}
+	an, _ := MatMul(a, null)
+	if an.Norm(EuclideanNorm) > 1e-9 {
+		t.Fatalf("null space is wrong: A * N =\n %s", an.ToStr())
+	}
+
+	rng, err := a.Range()
+	if err != nil {
+		t.Fatal(err)
+	} else if rng.rows != 5 || rng.cols != 3 {
+		t.Fatalf("range dims are wrong: expected 5x3, got %dx%d", rng.rows, rng.cols)
+	}
+	// projecting the columns of A onto the range keeps them intact
+	coef, _ := MatMul(TransposeMat(rng), a)
+	proj, _ := MatMul(rng, coef)
+	if !MatsEq(proj, a, 1e-9) {
+		t.Fatalf("range is wrong: projection of A is\n %s", proj.ToStr())
+	} else {
+		t.Log("rank, null space and range work correct")
+	}
+}
+
+func TestMatrix_PseudoInverse(t *testing.T) {
+	data := randData(8, 5, -10, 10)
+	a, _ := InitMat(data)
+
+	pinv, err := a.PseudoInverse()
+	if err != nil {
+		t.Fatal(err)
+	} else if pinv.rows != 5 || pinv.cols != 8 {
+		t.Fatalf("pseudo inverse dims are wrong: expected 5x8, got %dx%d", pinv.rows, pinv.cols)
+	}
+
+	ap, _ := MatMul(a, pinv)
+	apa, _ := MatMul(ap, a)
+	if !MatsEq(apa, a, 1e-9) {
+		t.Fatal("A * A^+ * A != A")
+	}
+	pa, _ := MatMul(pinv, a)
+	pap, _ := MatMul(pa, pinv)
+	if !MatsEq(pap, pinv, 1e-9) {
+		t.Fatal("A^+ * A * A^+ != A^+")
+	}
+
+	sq := randData(10, 10, 1, 10)
+	sqMat, _ := InitMat(sq)
+	cond, err := sqMat.Cond2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sqVec []float64
+	for i := range sq {
+		sqVec = append(sqVec, sq[i]...)
+	}
+	lapackCond := mat.Cond(mat.NewDense(10, 10, sqVec), 2)
+	if math.Abs(cond - lapackCond) > 1e-6 * lapackCond {
+		t.Fatalf("cond is wrong: expected %0.15f, got %0.15f", lapackCond, cond)
+	} else {
+		t.Log("pseudo inverse and cond work correct")
+	}
+}
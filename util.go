@@ -1,17 +1,31 @@
 package algnum
 
+import "math"
+
+const machEps = 0x1p-52
+
+// the rows are consecutive parts of one backing array, so matOfRows
+// wraps them without copying
 func init2dSlice(rows, cols int) [][]float64 {
 	data := make([][]float64, rows)
+	backing := make([]float64, rows * cols)
 	for i := range data {
-		data[i] = make([]float64, cols)
+		data[i] = backing[i * cols : (i + 1) * cols]
 	}
 	return data
 }
 
 func copy2dSlice(src [][]float64) [][]float64 {
+	var size int
+	for i := range src {
+		size += len(src[i])
+	}
+
 	dst := make([][]float64, len(src))
+	backing := make([]float64, size)
 	for i := range dst {
-		dst[i] = make([]float64, len(src[i]))
+		dst[i] = backing[ : len(src[i])]
+		backing = backing[len(src[i]) : ]
 		copy(dst[i], src[i])
 	}
 
@@ -29,6 +43,13 @@ func intMax(a, b int) int {
 	return b
 }
 
+func intMin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 func intMax4(a, b, c, d int) int {
 	return intMax(intMax(intMax(a, b), c), d)
 }
@@ -45,17 +66,32 @@ func nearestPowerOfTwo(a int) int {
 	return 1 << count
 }
 
-func findMinMax(a []float64) (float64, float64) {
-	min, max := a[0], a[0]
-
-	for _, ai := range a {
-		if ai < min {
-			min = ai
+func maxAbs(data [][]float64) float64 {
+	var max float64
+	for i := range data {
+		for _, v := range data[i] {
+			max = math.Max(max, math.Abs(v))
 		}
-		if ai > max {
-			max = ai
+	}
+	return max
+}
+
+func maxAbsUpper(data [][]float64) float64 {
+	var max float64
+	for i := range data {
+		for j := i; j < len(data[i]); j++ {
+			max = math.Max(max, math.Abs(data[i][j]))
 		}
 	}
+	return max
+}
 
-	return min, max
-}
\ No newline at end of file
+func transpose2dSlice(data [][]float64, rows, cols int) [][]float64 {
+	res := init2dSlice(cols, rows)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			res[j][i] = data[i][j]
+		}
+	}
+	return res
+}
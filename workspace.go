@@ -0,0 +1,174 @@
+package algnum
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// Workspace keeps released temporaries for reuse. A routine given a
+// workspace takes its scratch vectors and matrices from it and gives them
+// back before returning, so repeated calls sharing one workspace allocate
+// little more than their results. It may be shared by goroutines
+type Workspace struct {
+	mu sync.Mutex
+	// free vectors by capacity, class c holds capacity 1 << c
+	free [bits.UintSize][][]float64
+	ints [bits.UintSize][][]int
+	// row headers of matrices
+	rows [bits.UintSize][][][]float64
+	// spare matrix headers
+	mats []*Matrix
+}
+
+func NewWorkspace() *Workspace {
+	return &Workspace{}
+}
+
+// workspaces of the calls that take no options
+var workspacePool = sync.Pool{New: func() interface{} {
+	return NewWorkspace()
+}}
+
+func sizeClass(n int) int {
+	return bits.Len(uint(n - 1))
+}
+
+// zeroed vector of length n
+func (w *Workspace) vec(n int) []float64 {
+	if n == 0 {
+		return nil
+	}
+	c := sizeClass(n)
+
+	w.mu.Lock()
+	var v []float64
+	if k := len(w.free[c]); k > 0 {
+		v = w.free[c][k - 1]
+		w.free[c] = w.free[c][ : k - 1]
+	}
+	w.mu.Unlock()
+
+	if v == nil {
+		return make([]float64, n, 1 << c)
+	}
+	v = v[ : n]
+	for i := range v {
+		v[i] = 0
+	}
+	return v
+}
+
+// v must come from vec and mustn't be used afterwards
+func (w *Workspace) release(v []float64) {
+	if cap(v) == 0 {
+		return
+	}
+	c := sizeClass(cap(v))
+	w.mu.Lock()
+	w.free[c] = append(w.free[c], v[ : 0])
+	w.mu.Unlock()
+}
+
+// int vector of length n, not zeroed
+func (w *Workspace) intVec(n int) []int {
+	if n == 0 {
+		return nil
+	}
+	c := sizeClass(n)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if k := len(w.ints[c]); k > 0 {
+		v := w.ints[c][k - 1]
+		w.ints[c] = w.ints[c][ : k - 1]
+		return v[ : n]
+	}
+	return make([]int, n, 1 << c)
+}
+
+func (w *Workspace) releaseInts(v []int) {
+	if cap(v) == 0 {
+		return
+	}
+	c := sizeClass(cap(v))
+	w.mu.Lock()
+	w.ints[c] = append(w.ints[c], v[ : 0])
+	w.mu.Unlock()
+}
+
+// like m.rowSlices, with the headers from w
+func (w *Workspace) rowsOf(m *Matrix) [][]float64 {
+	if m.rows == 0 {
+		return nil
+	}
+	c := sizeClass(m.rows)
+	w.mu.Lock()
+	var res [][]float64
+	if k := len(w.rows[c]); k > 0 {
+		res = w.rows[c][k - 1][ : m.rows]
+		w.rows[c] = w.rows[c][ : k - 1]
+	}
+	w.mu.Unlock()
+
+	if res == nil {
+		res = make([][]float64, m.rows, 1 << c)
+	}
+	for i := range res {
+		res[i] = m.row(i)
+	}
+	return res
+}
+
+func (w *Workspace) releaseRows(r [][]float64) {
+	if cap(r) == 0 {
+		return
+	}
+	for i := range r {
+		r[i] = nil
+	}
+	c := sizeClass(cap(r))
+	w.mu.Lock()
+	w.rows[c] = append(w.rows[c], r[ : 0])
+	w.mu.Unlock()
+}
+
+func releaseVecs(w *Workspace, vs ...[]float64) {
+	for _, v := range vs {
+		w.release(v)
+	}
+}
+
+func (w *Workspace) header() *Matrix {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if k := len(w.mats); k > 0 {
+		m := w.mats[k - 1]
+		w.mats = w.mats[ : k - 1]
+		return m
+	}
+	return &Matrix{}
+}
+
+// zeroed matrix with its own storage
+func (w *Workspace) mat(rows, cols int) *Matrix {
+	m := w.header()
+	*m = Matrix{w.vec(rows * cols), rows, cols, cols}
+	return m
+}
+
+// the view shares the storage of m but its header comes from w
+func (w *Workspace) view(m *Matrix, i, j, rows, cols int) *Matrix {
+	v := w.header()
+	*v = m.viewOf(i, j, rows, cols)
+	return v
+}
+
+// gives back the header and, unless m is a view, the storage
+func (w *Workspace) releaseMat(m *Matrix, view bool) {
+	if !view {
+		w.release(m.data)
+	}
+	*m = Matrix{}
+	w.mu.Lock()
+	w.mats = append(w.mats, m)
+	w.mu.Unlock()
+}
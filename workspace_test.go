@@ -0,0 +1,194 @@
+package algnum
+
+import (
+	"math"
+	"runtime"
+	"testing"
+)
+
+// bytes allocated by one call of f, averaged over runs after a warm up
+func allocBytes(runs int, f func()) uint64 {
+	f()
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for i := 0; i < runs; i++ {
+		f()
+	}
+	runtime.ReadMemStats(&after)
+	return (after.TotalAlloc - before.TotalAlloc) / uint64(runs)
+}
+
+func TestWorkspace(t *testing.T) {
+	ws := NewWorkspace()
+	v := ws.vec(100)
+	if len(v) != 100 || cap(v) != 128 {
+		t.Fatalf("vector has length %d and capacity %d, expected 100 and 128", len(v), cap(v))
+	}
+	for i := range v {
+		v[i] = 1
+	}
+	ws.release(v)
+	u := ws.vec(70)
+	if &u[0] != &v[0] {
+		t.Fatal("released vector isn't reused")
+	}
+	for i := range u {
+		if u[i] != 0 {
+			t.Fatal("reused vector isn't zeroed")
+		}
+	}
+	if w := ws.vec(70); &w[0] == &u[0] {
+		t.Fatal("vector is handed out twice")
+	}
+
+	m := ws.mat(3, 5)
+	view := ws.view(m, 1, 1, 2, 3)
+	view.data[0] = 7
+	if m.data[6] != 7 {
+		t.Fatal("view doesn't share storage")
+	}
+	ws.releaseMat(view, true)
+	ws.releaseMat(m, false)
+	if h := ws.header(); h != m {
+		t.Fatal("released header isn't reused")
+	}
+	t.Log("workspace works correct")
+}
+
+func TestWorkspaceAllocs(t *testing.T) {
+	n := 256
+	a, b := matOfRows(randData(n, n, -10, 10), n, n), matOfRows(randData(n, n, -10, 10), n, n)
+	expected := gonumMul(a, b)
+	ws := NewWorkspace()
+	for _, winograd := range []bool{false, true} {
+		fresh := allocBytes(5, func() {
+			StrassenWithOptions(a, b, &StrassenOptions{Cutoff: 16, Winograd: winograd})
+		})
+		var res *Matrix
+		shared := allocBytes(5, func() {
+			res, _ = StrassenWithOptions(a, b, &StrassenOptions{Cutoff: 16, Winograd: winograd, Workspace: ws})
+		})
+		if !MatsEq(expected, res, 1e-9) {
+			t.Fatalf("result is wrong for %dx%d, winograd %t", n, n, winograd)
+		}
+		// only the result with its n * n * 8 bytes and a few headers
+		allocs := testing.AllocsPerRun(5, func() {
+			StrassenWithOptions(a, b, &StrassenOptions{Cutoff: 16, Winograd: winograd, Workspace: ws})
+		})
+		if shared > uint64(n * n * 8 + 256) || allocs > 6 {
+			t.Fatalf("strassen with shared workspace allocates %d bytes in %g allocations per call, %d without", shared, allocs, fresh)
+		}
+		t.Logf("strassen allocates %d bytes per call with shared workspace, %d without", shared, fresh)
+	}
+
+	k := 12
+	f := randFree(k * k, 1, 10)
+	lapack, err := lapackSolve(laplacian2dData(k), k * k, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &SolveOptions{Workspace: ws}
+	for name, solve := range map[string]func() (*SolveResult, error){
+		"cg": func() (*SolveResult, error) { return ConjugateGradient(laplacian2d{k}, f, opts) },
+		"gmres": func() (*SolveResult, error) { return GMRES(laplacian2d{k}, f, 0, opts) },
+		"bicgstab": func() (*SolveResult, error) { return BiCGSTAB(laplacian2d{k}, f, opts) },
+	} {
+		var res *SolveResult
+		shared := allocBytes(5, func() {
+			res, err = solve()
+		})
+		if err != nil {
+			t.Fatal(err)
+		} else if !VectsEq(lapack, res.X, 1e-8) {
+			t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(lapack), VectToStr(res.X))
+		}
+		// x and the residual history, which grows by doubling
+		limit := uint64(8 * (k * k + 4 * (res.Iterations + 1)) + 256)
+		if shared > limit {
+			t.Fatalf("%s with shared workspace allocates %d bytes per call, at most %d expected", name, shared, limit)
+		}
+		t.Logf("%s allocates %d bytes per call with shared workspace", name, shared)
+	}
+}
+
+func TestWorkspaceAllocsLU(t *testing.T) {
+	n := 50
+	a := matOfRows(randData(n, n, -10, 10), n, n)
+	sym, _ := MatMul(a, TransposeMat(a))
+	b, x := randFree(n, 1, 10), make([]float64, n)
+	ws := NewWorkspace()
+
+	f, err := FactorizeLUWithWorkspace(a, ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = f.SolveTo(x, b)
+	if res, _ := MatVecMul(a, x); !VectsEq(b, res, 1e-8) {
+		t.Fatalf("result is wrong: expected\n %s,\ngot\n %s", VectToStr(b), VectToStr(res))
+	}
+	f.Release()
+	if _, err := FactorizeLUWithWorkspace(matOfRows([][]float64{{1, 2}, {2, 4}}, 2, 2), ws); err == nil {
+		t.Fatal("expected singular matrix error")
+	}
+
+	// the factor header is all a factorization allocates, a solve nothing
+	if allocs := testing.AllocsPerRun(5, func() {
+		f, _ := FactorizeLUWithWorkspace(a, ws)
+		f.Release()
+	}); allocs > 1 {
+		t.Fatalf("lu with shared workspace makes %g allocations per call", allocs)
+	}
+	f, _ = FactorizeLUWithWorkspace(a, ws)
+	if allocs := testing.AllocsPerRun(5, func() {
+		_ = f.SolveTo(x, b)
+	}); allocs > 0 {
+		t.Fatalf("lu solve makes %g allocations per call", allocs)
+	}
+	f.Release()
+
+	// the inverse uses a pooled workspace, so beyond the result only
+	// the tasks allocate, unless the pool loses its items
+	if raceEnabled {
+		t.Log("pooled inverse allocations aren't checked under the race detector")
+	} else if bytes := allocBytes(5, func() { _, _ = a.Inverse() }); bytes > uint64(8 * n * n + 1024) {
+		t.Fatalf("inverse allocates %d bytes per call", bytes)
+	}
+
+	// eigenpairs allocate their vectors and nothing per iteration
+	opts := &PowerOptions{Shift: 3, Workspace: ws}
+	for name, eigen := range map[string]func() (*EigenPair, error){
+		"power": func() (*EigenPair, error) {
+			pairs, err := PowerIteration(sym, opts)
+			return &pairs[0], err
+		},
+		"inverse": func() (*EigenPair, error) { return InverseIteration(sym, opts) },
+		"rqi": func() (*EigenPair, error) { return RayleighQuotientIteration(sym, opts) },
+	} {
+		var pair *EigenPair
+		bytes := allocBytes(5, func() {
+			pair, err = eigen()
+		})
+		if err != nil {
+			t.Fatal(err)
+		} else if pair.Residual > 1e-6 * math.Max(1, math.Abs(pair.Value)) {
+			t.Fatalf("%s residual %g is too large", name, pair.Residual)
+		}
+		if bytes > uint64(8 * n + 256) {
+			t.Fatalf("%s with shared workspace allocates %d bytes per call after %d iterations", name, bytes, pair.Iterations)
+		}
+	}
+
+	t.Log("lu, inverse and eigenpairs with workspace work correct")
+}
+
+func BenchmarkStrassenWorkspace512(b *testing.B) {
+	n := 512
+	x, y := matOfRows(randData(n, n, -10, 10), n, n), matOfRows(randData(n, n, -10, 10), n, n)
+	opts := &StrassenOptions{Workspace: NewWorkspace()}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		StrassenWithOptions(x, y, opts)
+	}
+}